@@ -0,0 +1,150 @@
+// Package orderedmap provides the map keeping its entries in the order they are added,
+// on which the objects of JSON and the dictionaries of YAML are built.
+package orderedmap
+
+// IndexThreshold is the number of the entries from which maps index their entries by their keys.
+// Scanning the entries is as fast as the index for the smaller maps, which saves the memory for the index.
+const IndexThreshold = 8
+
+// Map keeps the entries in the order, where the entries with the same key are all kept and the last one is effective.
+// The deleted entries are left as tombstones until they are more than the live ones
+// so that deleting an entry takes constant time in amortization.
+//
+// The entries are referred to by their indexes, which are stable until an entry is deleted.
+// The zero value is an empty map.
+type Map struct {
+	entries []entry
+	// lasts maps each key to the index of the last entry with the key only if the entries are many enough.
+	lasts map[string]int
+	// deleted is the number of the tombstones in entries.
+	deleted int
+}
+
+type entry struct {
+	key string
+	val interface{}
+	// shadowed is the index of the previous entry with the same key, or -1 if there is no such entry.
+	shadowed int
+	deleted  bool
+}
+
+// Len returns the number of the entries including the ones with the same key.
+func (m *Map) Len() int {
+	return len(m.entries) - m.deleted
+}
+
+// Lookup returns the index of the last entry with the given key, or -1 if there is no such entry.
+func (m *Map) Lookup(key string) int {
+	if m.lasts != nil {
+		if i, ok := m.lasts[key]; ok {
+			return i
+		}
+		return -1
+	}
+
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if entry := m.entries[i]; !entry.deleted && entry.key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// At returns the value of the entry at the given index.
+func (m *Map) At(i int) interface{} {
+	return m.entries[i].val
+}
+
+// SetAt replaces the value of the entry at the given index.
+func (m *Map) SetAt(i int, val interface{}) {
+	m.entries[i].val = val
+}
+
+// Append appends the entry keeping the entries with the same key.
+func (m *Map) Append(key string, val interface{}) {
+	m.entries = append(m.entries, entry{
+		key:      key,
+		val:      val,
+		shadowed: m.Lookup(key),
+	})
+
+	switch {
+	case m.lasts != nil:
+		m.lasts[key] = len(m.entries) - 1
+	case m.Len() >= IndexThreshold:
+		m.lasts = make(map[string]int, len(m.entries))
+		for i, entry := range m.entries {
+			if !entry.deleted {
+				m.lasts[entry.key] = i
+			}
+		}
+	}
+}
+
+// Delete deletes all the entries with the given key, and reports whether the map had them.
+func (m *Map) Delete(key string) bool {
+	i := m.Lookup(key)
+	if i < 0 {
+		return false
+	}
+
+	for ; i >= 0; i = m.entries[i].shadowed {
+		m.entries[i] = entry{
+			shadowed: m.entries[i].shadowed,
+			deleted:  true,
+		}
+		m.deleted++
+	}
+	if m.lasts != nil {
+		delete(m.lasts, key)
+	}
+
+	if m.deleted > m.Len() {
+		m.compact()
+	}
+
+	return true
+}
+
+// compact drops the tombstones.
+func (m *Map) compact() {
+	entries := m.entries
+	m.entries, m.lasts, m.deleted = make([]entry, 0, m.Len()), nil, 0
+	for _, entry := range entries {
+		if !entry.deleted {
+			m.Append(entry.key, entry.val)
+		}
+	}
+}
+
+// Range calls fn with the index, the key and the value of each entry in the order until fn returns false.
+// The entries with the same key are all visited.
+func (m *Map) Range(fn func(i int, key string, val interface{}) bool) {
+	for i, entry := range m.entries {
+		if entry.deleted {
+			continue
+		}
+		if !fn(i, entry.key, entry.val) {
+			return
+		}
+	}
+}
+
+// Clone returns the copy of the map which can be modified independently.
+// The values of the entries are shared between them.
+func (m *Map) Clone() *Map {
+	cloned := &Map{
+		entries: make([]entry, len(m.entries)),
+		deleted: m.deleted,
+	}
+	copy(cloned.entries, m.entries)
+	if m.lasts != nil {
+		cloned.lasts = make(map[string]int, len(m.lasts))
+		for key, i := range m.lasts {
+			cloned.lasts[key] = i
+		}
+	}
+
+	return cloned
+}
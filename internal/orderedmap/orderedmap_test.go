@@ -0,0 +1,137 @@
+package orderedmap
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	tests := map[string]struct {
+		update   func(m *Map)
+		expected []string
+	}{
+		"append": {
+			update: func(m *Map) {
+				m.Append("b", 1)
+				m.Append("a", 2)
+			},
+			expected: []string{"b:1", "a:2"},
+		},
+		"append duplicate key": {
+			update: func(m *Map) {
+				m.Append("a", 1)
+				m.Append("b", 2)
+				m.Append("a", 3)
+			},
+			expected: []string{"a:1", "b:2", "a:3"},
+		},
+		"set last entry": {
+			update: func(m *Map) {
+				m.Append("a", 1)
+				m.Append("a", 2)
+				m.SetAt(m.Lookup("a"), 3)
+			},
+			expected: []string{"a:1", "a:3"},
+		},
+		"delete duplicate key": {
+			update: func(m *Map) {
+				m.Append("a", 1)
+				m.Append("b", 2)
+				m.Append("a", 3)
+				m.Delete("a")
+			},
+			expected: []string{"b:2"},
+		},
+		"append deleted key": {
+			update: func(m *Map) {
+				m.Append("a", 1)
+				m.Append("b", 2)
+				m.Delete("a")
+				m.Append("a", 3)
+			},
+			expected: []string{"b:2", "a:3"},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			m := &Map{}
+			test.update(m)
+			if actual := entriesOf(m); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("should have updated: %s", reportUnexpected("entries", actual, test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestMapWithManyEntries(t *testing.T) {
+	m := &Map{}
+	for i := 0; i < IndexThreshold*4; i++ {
+		m.Append(fmt.Sprint(i), i)
+	}
+	for i := 0; i < IndexThreshold*4; i += 2 {
+		if !m.Delete(fmt.Sprint(i)) {
+			t.Errorf("should have deleted %d", i)
+			return
+		}
+	}
+	m.Append("0", -1)
+
+	var expected []string
+	for i := 1; i < IndexThreshold*4; i += 2 {
+		expected = append(expected, fmt.Sprintf("%d:%d", i, i))
+	}
+	expected = append(expected, "0:-1")
+
+	if actual := entriesOf(m); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("should have kept the order: %s", reportUnexpected("entries", actual, expected))
+		return
+	}
+	for i := 1; i < IndexThreshold*4; i += 2 {
+		j := m.Lookup(fmt.Sprint(i))
+		if j < 0 || m.At(j) != i {
+			t.Errorf("should have looked up %d", i)
+			return
+		}
+	}
+	if m.Lookup("2") >= 0 {
+		t.Errorf("should not have looked up deleted key")
+		return
+	}
+}
+
+func TestMapClone(t *testing.T) {
+	m := &Map{}
+	m.Append("a", 1)
+	m.Append("b", 2)
+
+	cloned := m.Clone()
+	cloned.SetAt(cloned.Lookup("a"), 3)
+	cloned.Delete("b")
+	cloned.Append("c", 4)
+
+	if actual, expected := entriesOf(m), []string{"a:1", "b:2"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("should not have modified the original: %s", reportUnexpected("entries", actual, expected))
+		return
+	}
+	if actual, expected := entriesOf(cloned), []string{"a:3", "c:4"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("should have modified the clone: %s", reportUnexpected("entries", actual, expected))
+		return
+	}
+}
+
+func entriesOf(m *Map) []string {
+	var entries []string
+	m.Range(func(_ int, key string, val interface{}) bool {
+		entries = append(entries, fmt.Sprintf("%s:%v", key, val))
+		return true
+	})
+
+	return entries
+}
+
+func reportUnexpected(name string, actual, expected interface{}) error {
+	return fmt.Errorf("unexpected %s: got %v, expected %v", name, actual, expected)
+}
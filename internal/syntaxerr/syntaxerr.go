@@ -0,0 +1,85 @@
+// Package syntaxerr provides the syntax error which the parsers in this repository share.
+package syntaxerr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntaxError describes where and why the source is not valid.
+type SyntaxError struct {
+	Msg string
+	// Line and Column are 1-based, and Column counts characters rather than bytes, where a tab is a character.
+	Line, Column int
+	// Offset is the 0-based byte offset from the beginning of the source.
+	Offset int
+	// Expected lists the kinds of token which are expected at the position, if any,
+	// and Found is the kind of token which is found there.
+	Expected []string
+	Found    string
+	// Excerpt is the erroneous line followed by a line with a caret under the column.
+	// It is empty if the line is no longer available.
+	Excerpt string
+}
+
+func (e *SyntaxError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "syntax error at line %d, column %d: %s", e.Line, e.Column, e.Msg)
+	if len(e.Expected) != 0 {
+		fmt.Fprintf(&b, ": expected %s, but found '%s'", joinExpected(e.Expected), e.Found)
+	}
+
+	return b.String()
+}
+
+func joinExpected(expected []string) string {
+	quoted := make([]string, len(expected))
+	for i, e := range expected {
+		quoted[i] = fmt.Sprintf("'%s'", e)
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
+// ExcerptWidth is the number of characters around the column which excerpts show.
+const ExcerptWidth = 40
+
+// Excerpt returns the given line with a caret under the 0-based column,
+// or empty string if the column is out of the line.
+// The carriage returns in the line are dropped, and the tabs before the column are kept under it
+// so that the caret is aligned however the tabs are shown.
+func Excerpt(line []rune, col int) string {
+	if col < 0 || len(line) < col {
+		return ""
+	}
+
+	from, to := col-ExcerptWidth, col+ExcerptWidth
+	if from < 0 {
+		from = 0
+	}
+	if to > len(line) {
+		to = len(line)
+	}
+
+	var b strings.Builder
+	for _, c := range line[from:to] {
+		if c == '\r' {
+			continue
+		}
+		b.WriteRune(c)
+	}
+	b.WriteRune('\n')
+	for _, c := range line[from:col] {
+		if c == '\t' {
+			b.WriteRune('\t')
+			continue
+		}
+		b.WriteRune(' ')
+	}
+	b.WriteRune('^')
+
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package syntaxerr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSyntaxErrorError(t *testing.T) {
+	tests := map[string]struct {
+		err      SyntaxError
+		expected string
+	}{
+		"message": {
+			err:      SyntaxError{Msg: "invalid number", Line: 1, Column: 2},
+			expected: "syntax error at line 1, column 2: invalid number",
+		},
+		"one expected": {
+			err:      SyntaxError{Msg: "unexpected token", Line: 2, Column: 3, Expected: []string{":"}, Found: "number"},
+			expected: "syntax error at line 2, column 3: unexpected token: expected ':', but found 'number'",
+		},
+		"many expected": {
+			err:      SyntaxError{Msg: "unexpected token", Line: 1, Column: 6, Expected: []string{",", "]", "}"}, Found: "EOF"},
+			expected: "syntax error at line 1, column 6: unexpected token: expected ',', ']' or '}', but found 'EOF'",
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			if actual := test.err.Error(); actual != test.expected {
+				t.Errorf("should have described the error: %s", reportUnexpected("message", actual, test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	long := strings.Repeat("a", ExcerptWidth*2) + "b" + strings.Repeat("c", ExcerptWidth*2)
+
+	tests := map[string]struct {
+		line     string
+		col      int
+		expected string
+	}{
+		"column": {
+			line:     "[1, 2}",
+			col:      5,
+			expected: "[1, 2}\n     ^",
+		},
+		"end of line": {
+			line:     "[1,",
+			col:      3,
+			expected: "[1,\n   ^",
+		},
+		"tabs and carriage return": {
+			line:     "\t\ta: b\r",
+			col:      5,
+			expected: "\t\ta: b\n\t\t   ^",
+		},
+		"long line": {
+			line:     long,
+			col:      ExcerptWidth * 2,
+			expected: strings.Repeat("a", ExcerptWidth) + "b" + strings.Repeat("c", ExcerptWidth-1) + "\n" + strings.Repeat(" ", ExcerptWidth) + "^",
+		},
+		"out of line": {
+			line: "[1]",
+			col:  4,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			if actual := Excerpt([]rune(test.line), test.col); actual != test.expected {
+				t.Errorf("should have excerpted the line: %s", reportUnexpected("excerpt", fmt.Sprintf("%q", actual), fmt.Sprintf("%q", test.expected)))
+				return
+			}
+		})
+	}
+}
+
+func reportUnexpected(name string, actual, expected interface{}) error {
+	return fmt.Errorf("unexpected %s: got %v, expected %v", name, actual, expected)
+}
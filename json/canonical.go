@@ -0,0 +1,157 @@
+package json
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Canonicalize returns the canonical form of the given value defined by RFC 8785 JSON Canonicalization Scheme,
+// which is the same bytes for the values which are equal so that they can be hashed and signed.
+//
+// The props of objects are sorted by the UTF-16 code units of their keys,
+// numbers are formatted in the same way as ECMAScript formats the nearest float64,
+// and strings escape only the characters which RFC 8259 requires to be escaped.
+// It returns an error for the objects which have duplicate keys as the scheme does not allow them,
+// and for the numbers which float64 cannot represent.
+func Canonicalize(v Value) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := canonicalize(&buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// CanonicalSHA256 returns the SHA-256 digest of the canonical form of the given value.
+func CanonicalSHA256(v Value) ([sha256.Size]byte, error) {
+	canonical, err := Canonicalize(v)
+	if err != nil {
+		return [sha256.Size]byte{}, fmt.Errorf("failed to canonicalize: %w", err)
+	}
+
+	return sha256.Sum256(canonical), nil
+}
+
+// IsCanonical reports whether the given source is already in the canonical form.
+// The source which is not valid JSON is not canonical.
+func IsCanonical(src []byte) bool {
+	val, err := Parse(src)
+	if err != nil {
+		return false
+	}
+
+	canonical, err := Canonicalize(val)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(canonical, src)
+}
+
+func canonicalize(buf *bytes.Buffer, v Value) error {
+	switch v := v.(type) {
+	case Null:
+		buf.WriteString(literalNull)
+	case Bool:
+		if v {
+			buf.WriteString(literalTrue)
+		} else {
+			buf.WriteString(literalFalse)
+		}
+	case Num:
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+		formatted, err := formatFloat(f, 64)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(formatted)
+	case String:
+		return canonicalizeString(buf, string(v))
+	case Array:
+		buf.WriteByte('[')
+		for i, elem := range v {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			if err := canonicalize(buf, elem); err != nil {
+				return fmt.Errorf("failed to canonicalize value at %d: %w", i, err)
+			}
+		}
+		buf.WriteByte(']')
+	case Object:
+		return canonicalizeObject(buf, v)
+	default:
+		return fmt.Errorf("unknown type of value: %T", v)
+	}
+
+	return nil
+}
+
+func canonicalizeObject(buf *bytes.Buffer, obj Object) error {
+	props := obj.Props()
+	// The keys are compared as the sequences of UTF-16 code units, which differs from comparing them as UTF-8 bytes
+	// for the characters beyond the BMP, whose surrogates precede the characters from U+E000.
+	keys := make([][]uint16, len(props))
+	for i, prop := range props {
+		keys[i] = utf16.Encode([]rune(string(prop.key)))
+	}
+	indexes := make([]int, len(props))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return compareUTF16(keys[indexes[i]], keys[indexes[j]]) < 0
+	})
+
+	buf.WriteByte('{')
+	for n, i := range indexes {
+		prop := props[i]
+		if n != 0 {
+			if compareUTF16(keys[indexes[n-1]], keys[i]) == 0 {
+				return fmt.Errorf("object should not have duplicate key %q", prop.key)
+			}
+			buf.WriteByte(',')
+		}
+
+		if err := canonicalizeString(buf, string(prop.key)); err != nil {
+			return fmt.Errorf("failed to canonicalize key %q: %w", prop.key, err)
+		}
+		buf.WriteByte(':')
+		if err := canonicalize(buf, prop.val); err != nil {
+			return fmt.Errorf("failed to canonicalize value of %s: %w", prop.key, err)
+		}
+	}
+	buf.WriteByte('}')
+
+	return nil
+}
+
+func compareUTF16(a, b []uint16) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+func canonicalizeString(buf *bytes.Buffer, s string) error {
+	if !utf8.ValidString(s) {
+		return fmt.Errorf("string should be valid UTF-8: %q", s)
+	}
+
+	buf.WriteString(quoteString(s, false, false))
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package json
+
+import (
+	"crypto/sha256"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected string
+	}{
+		"literals": {
+			src:      `[ null, true, false ]`,
+			expected: `[null,true,false]`,
+		},
+		"numbers": {
+			src:      `[333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001, -0, 1e21, 1e20, 9007199254740993, 1e-7, 0.000001]`,
+			expected: `[333333333.3333333,1e+30,4.5,0.002,1e-27,0,1e+21,100000000000000000000,9007199254740992,1e-7,0.000001]`,
+		},
+		"strings": {
+			src:      `"\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/\u2028"`,
+			expected: "\"€$\\u000f\\nA'B\\\"\\\\\\\\\\\"/\u2028\"",
+		},
+		"keys in utf-16 order": {
+			src:      `{"\u20ac": 1, "\r": 2, "\ufb33": 3, "1": 4, "\ud83d\ude00": 5, "\u0080": 6, "\u00f6": 7}`,
+			expected: "{\"\\r\":2,\"1\":4,\"\u0080\":6,\"\u00f6\":7,\"\u20ac\":1,\"\U0001f600\":5,\"\ufb33\":3}",
+		},
+		"nested": {
+			src: `{
+				"b": [ {"d": 1.0, "c": "x"} ],
+				"a": {}
+			}`,
+			expected: `{"a":{},"b":[{"c":"x","d":1}]}`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			val, err := ParseString(test.src)
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+
+			actual, err := Canonicalize(val)
+			if err != nil {
+				t.Errorf("should have canonicalized: %s", err)
+				return
+			}
+			if string(actual) != test.expected {
+				t.Errorf("should have canonicalized: %s", reportUnexpected("canonical form", string(actual), test.expected))
+				return
+			}
+			if !IsCanonical(actual) {
+				t.Errorf("should have reported that the canonical form is canonical: %s", actual)
+				return
+			}
+		})
+	}
+}
+
+func TestCanonicalizeFails(t *testing.T) {
+	tests := map[string]Value{
+		"duplicate keys":     NewObject(NewProp("a", Num{literal: "1"}), NewProp("a", Num{literal: "2"})),
+		"too large number":   Array{Num{literal: "1e400"}},
+		"invalid utf-8":      String("\xff"),
+		"invalid utf-8 key":  NewObject(NewProp("\xff", Null{})),
+		"non-finite number":  Num{literal: "NaN"},
+		"nested duplicates":  Array{NewObject(NewProp("b", Null{}), NewProp("b", Null{}))},
+		"unknown type value": nil,
+	}
+
+	for n, val := range tests {
+		t.Run(n, func(t *testing.T) {
+			if _, err := Canonicalize(val); err == nil {
+				t.Errorf("should have failed to canonicalize: %v", val)
+				return
+			}
+		})
+	}
+}
+
+func TestCanonicalSHA256(t *testing.T) {
+	a, err := ParseString(`{"b": 2.0, "a": [1, "x"]}`)
+	if err != nil {
+		t.Errorf("should have parsed: %s", err)
+		return
+	}
+	b, err := ParseString(`{"a":[1,"x"],"b":2}`)
+	if err != nil {
+		t.Errorf("should have parsed: %s", err)
+		return
+	}
+
+	actual, err := CanonicalSHA256(a)
+	if err != nil {
+		t.Errorf("should have hashed: %s", err)
+		return
+	}
+	expected := sha256.Sum256([]byte(`{"a":[1,"x"],"b":2}`))
+	if actual != expected {
+		t.Errorf("should have hashed the canonical form: %s", reportUnexpected("digest", actual, expected))
+		return
+	}
+
+	other, err := CanonicalSHA256(b)
+	if err != nil {
+		t.Errorf("should have hashed: %s", err)
+		return
+	}
+	if other != actual {
+		t.Errorf("should have hashed the equal values into the same digest: %s", reportUnexpected("digest", other, actual))
+		return
+	}
+}
+
+func TestIsCanonical(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected bool
+	}{
+		"canonical":          {src: `{"a":[1,"x"],"b":null}`, expected: true},
+		"whitespaces":        {src: `{"a": 1}`},
+		"trailing newline":   {src: `{"a":1}` + "\n"},
+		"unsorted keys":      {src: `{"b":1,"a":2}`},
+		"non-canonical num":  {src: `[1.0]`},
+		"escaped character":  {src: `"\u0041"`},
+		"escaped solidus":    {src: `"\/"`},
+		"upper case escapes": {src: `"\u001F"`},
+		"duplicate keys":     {src: `{"a":1,"a":1}`},
+		"invalid json":       {src: `{"a":1`},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			if actual := IsCanonical([]byte(test.src)); actual != test.expected {
+				t.Errorf("unexpected result for %s: %s", test.src, reportUnexpected("canonical", actual, test.expected))
+				return
+			}
+		})
+	}
+}
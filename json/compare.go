@@ -0,0 +1,258 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ChangeKind is the kind of Change.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is a difference between two values at a path.
+type Change struct {
+	Kind ChangeKind
+	// Path is the path to the value such as $.users[?@.id==1].name,
+	// where the elements of arrays compared as sets are identified by filters.
+	Path string
+	// From and To are the values before and after the change.
+	// From is nil for added values, and To is nil for removed values.
+	From, To Value
+}
+
+// Changes is the list of differences between two values.
+type Changes []Change
+
+// String returns the human readable report which has a line for each change such as
+//
+//	~ $.version: 1 -> 2
+//	+ $.tags[2]: "c"
+//	- $.legacy: true
+func (cs Changes) String() string {
+	var b strings.Builder
+	for _, c := range cs {
+		switch c.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "+ %s: %s\n", c.Path, compactString(c.To))
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "- %s: %s\n", c.Path, compactString(c.From))
+		default:
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", c.Path, compactString(c.From), compactString(c.To))
+		}
+	}
+
+	return b.String()
+}
+
+func compactString(v Value) string {
+	var buf bytes.Buffer
+	if err := (Encoder{}).encode(&buf, v, 0); err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return buf.String()
+}
+
+// MarshalJSONValue converts the changes into the array of objects such as
+// {"kind": "changed", "path": "$.version", "from": 1, "to": 2}, which does not have from or to if it is nil.
+func (cs Changes) MarshalJSONValue() (Value, error) {
+	arr := make(Array, len(cs))
+	for i, c := range cs {
+		obj := NewObject(
+			NewProp("kind", String(c.Kind)),
+			NewProp("path", String(c.Path)),
+		)
+		if c.From != nil {
+			obj.Set("from", c.From)
+		}
+		if c.To != nil {
+			obj.Set("to", c.To)
+		}
+		arr[i] = obj
+	}
+
+	return arr, nil
+}
+
+// Compare returns the differences between the given values with the default options,
+// where arrays are compared as ordered lists.
+func Compare(from, to Value) Changes {
+	return CompareOptions{}.Compare(from, to)
+}
+
+// CompareOptions is the options to compare values.
+type CompareOptions struct {
+	// ArrayKey is the key of the prop which identifies the objects in arrays.
+	// Arrays are compared as sets whose elements are matched by the values of the prop if it is not empty,
+	// and otherwise as ordered lists whose elements are matched by their indexes.
+	// Elements without the prop are matched by their whole values in sets.
+	ArrayKey string
+	// ArrayKeys overrides ArrayKey for the arrays at the paths such as $.groups[*].members,
+	// where every index is written as [*]. The empty key makes the array compared as ordered list.
+	ArrayKeys map[string]string
+}
+
+// Compare returns the differences between the given values in the order of the document.
+// Numbers are compared numerically, and objects are compared regardless of the order of their props.
+func (opts CompareOptions) Compare(from, to Value) Changes {
+	c := comparer{
+		opts: opts,
+	}
+	c.compare(nil, from, to)
+
+	return c.changes
+}
+
+type comparer struct {
+	opts    CompareOptions
+	changes Changes
+}
+
+func (c *comparer) compare(path valuePath, from, to Value) {
+	if equalValues(from, to) {
+		return
+	}
+
+	switch from := from.(type) {
+	case Object:
+		if to, ok := to.(Object); ok {
+			c.compareObjects(path, from, to)
+			return
+		}
+	case Array:
+		if to, ok := to.(Array); ok {
+			c.compareArrays(path, from, to)
+			return
+		}
+	}
+
+	c.add(ChangeChanged, path, from, to)
+}
+
+func (c *comparer) compareObjects(path valuePath, from, to Object) {
+	// Only the last one of the props with the same key is effective.
+	for _, prop := range from.effectiveProps() {
+		key := string(prop.key)
+		propPath := path.appended(keyElem(key))
+		if val, ok := to.Get(key); ok {
+			c.compare(propPath, prop.val, val)
+			continue
+		}
+
+		c.add(ChangeRemoved, propPath, prop.val, nil)
+	}
+
+	for _, prop := range to.effectiveProps() {
+		key := string(prop.key)
+		if from.Has(key) {
+			continue
+		}
+
+		c.add(ChangeAdded, path.appended(keyElem(key)), nil, prop.val)
+	}
+}
+
+func (c *comparer) compareArrays(path valuePath, from, to Array) {
+	if key := c.arrayKey(path); key != "" {
+		c.compareSets(path, from, to, key)
+		return
+	}
+
+	for i := 0; i < len(from) || i < len(to); i++ {
+		elemPath := path.appended(indexElem(i))
+		switch {
+		case i >= len(to):
+			c.add(ChangeRemoved, elemPath, from[i], nil)
+		case i >= len(from):
+			c.add(ChangeAdded, elemPath, nil, to[i])
+		default:
+			c.compare(elemPath, from[i], to[i])
+		}
+	}
+}
+
+func (c comparer) arrayKey(path valuePath) string {
+	if key, ok := c.opts.ArrayKeys[path.pattern()]; ok {
+		return key
+	}
+
+	return c.opts.ArrayKey
+}
+
+// compareSets compares the arrays as sets whose elements are identified by the values of the given key.
+func (c *comparer) compareSets(path valuePath, from, to Array, key string) {
+	matched := make([]bool, len(to))
+	for i, elem := range from {
+		id, hasID := elemID(elem, key)
+
+		j := -1
+		for k, other := range to {
+			if matched[k] {
+				continue
+			}
+
+			otherID, otherHasID := elemID(other, key)
+			if hasID && otherHasID && equalValues(id, otherID) || !hasID && !otherHasID && equalValues(elem, other) {
+				j = k
+				break
+			}
+		}
+
+		if j < 0 {
+			c.add(ChangeRemoved, setElemPath(path, i, key, id, hasID), elem, nil)
+			continue
+		}
+
+		matched[j] = true
+		c.compare(setElemPath(path, j, key, id, hasID), elem, to[j])
+	}
+
+	for j, elem := range to {
+		if matched[j] {
+			continue
+		}
+
+		id, hasID := elemID(elem, key)
+		c.add(ChangeAdded, setElemPath(path, j, key, id, hasID), nil, elem)
+	}
+}
+
+func elemID(elem Value, key string) (Value, bool) {
+	obj, ok := elem.(Object)
+	if !ok {
+		return nil, false
+	}
+
+	return obj.Get(key)
+}
+
+// setElemPath returns the path to the element of the set,
+// which has the filter by the id if the element has it and the index otherwise.
+func setElemPath(path valuePath, index int, key string, id Value, hasID bool) valuePath {
+	if !hasID {
+		return path.appended(indexElem(index))
+	}
+
+	var b strings.Builder
+	b.WriteByte('@')
+	writeKeyElem(&b, key)
+	b.WriteString("==")
+	b.WriteString(compactString(id))
+
+	return path.appended(filterElem(b.String()))
+}
+
+func (c *comparer) add(kind ChangeKind, path valuePath, from, to Value) {
+	c.changes = append(c.changes, Change{
+		Kind: kind,
+		Path: path.String(),
+		From: from,
+		To:   to,
+	})
+}
@@ -0,0 +1,120 @@
+package json
+
+import (
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := map[string]struct {
+		opts     CompareOptions
+		from, to string
+		expected string
+	}{
+		"equal": {
+			from:     `{"a": [1, {"b": 2}], "c": 1}`,
+			to:       `{"c": 1.0, "a": [1, {"b": 2}]}`,
+			expected: "",
+		},
+		"scalar": {
+			from:     `1`,
+			to:       `"1"`,
+			expected: "~ $: 1 -> \"1\"\n",
+		},
+		"props": {
+			from: `{"version": 1, "db": {"host": "a", "port": 5432}, "legacy": true}`,
+			to:   `{"version": 2, "db": {"host": "b", "port": 5432}, "first name": "x"}`,
+			expected: "~ $.version: 1 -> 2\n" +
+				"~ $.db.host: \"a\" -> \"b\"\n" +
+				"- $.legacy: true\n" +
+				"+ $[\"first name\"]: \"x\"\n",
+		},
+		"kind changed": {
+			from:     `{"a": [1]}`,
+			to:       `{"a": {"0": 1}}`,
+			expected: "~ $.a: [1] -> {\"0\":1}\n",
+		},
+		"ordered arrays": {
+			from: `{"tags": ["a", "b", "c"], "ports": [80, 443]}`,
+			to:   `{"tags": ["a", "x"], "ports": [80, 443, 8080]}`,
+			expected: "~ $.tags[1]: \"b\" -> \"x\"\n" +
+				"- $.tags[2]: \"c\"\n" +
+				"+ $.ports[2]: 8080\n",
+		},
+		"sets": {
+			opts: CompareOptions{ArrayKey: "id"},
+			from: `{"users": [{"id": 1, "name": "alice"}, {"id": 2, "name": "bob"}, {"id": 3, "name": "carol"}]}`,
+			to:   `{"users": [{"id": 3, "name": "carol"}, {"id": 1, "name": "alicia"}, {"id": 4, "name": "dave"}]}`,
+			expected: "~ $.users[?@.id==1].name: \"alice\" -> \"alicia\"\n" +
+				"- $.users[?@.id==2]: {\"id\":2,\"name\":\"bob\"}\n" +
+				"+ $.users[?@.id==4]: {\"id\":4,\"name\":\"dave\"}\n",
+		},
+		"sets with elements without key": {
+			opts:     CompareOptions{ArrayKey: "id"},
+			from:     `["a", "b", {"x": 1}]`,
+			to:       `[{"x": 1}, "c", "a"]`,
+			expected: "- $[1]: \"b\"\n+ $[1]: \"c\"\n",
+		},
+		"array keys by path": {
+			opts: CompareOptions{
+				ArrayKey: "id",
+				ArrayKeys: map[string]string{
+					"$.groups[*].members": "name",
+					"$.groups[*].tags":    "",
+				},
+			},
+			from: `{"groups": [{"id": "g", "members": [{"name": "a", "role": "owner"}], "tags": ["x", "y"]}]}`,
+			to:   `{"groups": [{"id": "g", "members": [{"name": "b", "role": "member"}, {"name": "a", "role": "member"}], "tags": ["y", "x"]}]}`,
+			expected: "~ $.groups[?@.id==\"g\"].members[?@.name==\"a\"].role: \"owner\" -> \"member\"\n" +
+				"+ $.groups[?@.id==\"g\"].members[?@.name==\"b\"]: {\"name\":\"b\",\"role\":\"member\"}\n" +
+				"~ $.groups[?@.id==\"g\"].tags[0]: \"x\" -> \"y\"\n" +
+				"~ $.groups[?@.id==\"g\"].tags[1]: \"y\" -> \"x\"\n",
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			from, err := ParseString(test.from)
+			if err != nil {
+				t.Errorf("should have parsed from: %s", err)
+				return
+			}
+			to, err := ParseString(test.to)
+			if err != nil {
+				t.Errorf("should have parsed to: %s", err)
+				return
+			}
+
+			actual := test.opts.Compare(from, to).String()
+			if actual != test.expected {
+				t.Errorf("should have compared: %s", reportUnexpected("report", actual, test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestMarshalChanges(t *testing.T) {
+	from, err := ParseString(`{"a": 1, "b": 2}`)
+	if err != nil {
+		t.Errorf("should have parsed from: %s", err)
+		return
+	}
+	to, err := ParseString(`{"a": 3, "c": null}`)
+	if err != nil {
+		t.Errorf("should have parsed to: %s", err)
+		return
+	}
+	expected := `[{"kind":"changed","path":"$.a","from":1,"to":3},` +
+		`{"kind":"removed","path":"$.b","from":2},` +
+		`{"kind":"added","path":"$.c","to":null}]`
+
+	actual, err := Marshal(Compare(from, to))
+	if err != nil {
+		t.Errorf("should have marshaled: %s", err)
+		return
+	}
+	if string(actual) != expected {
+		t.Errorf("should have marshaled: %s", reportUnexpected("output", string(actual), expected))
+		return
+	}
+}
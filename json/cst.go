@@ -0,0 +1,839 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ParseDocument parses the source as Document with the default options.
+func ParseDocument(src []byte) (*Document, error) {
+	return ParserOptions{}.ParseDocument(src)
+}
+
+// ParseDocument parses the source as Document, which should be a single value.
+// The props with the same key are kept as they are unless DuplicateKeys is DuplicateKeysError,
+// as the document cannot drop any part of the source.
+func (o ParserOptions) ParseDocument(src []byte) (*Document, error) {
+	if o.MaxDocumentSize > 0 && len(src) > o.MaxDocumentSize {
+		return nil, fmt.Errorf("failed to read: document should be at most %d bytes", o.MaxDocumentSize)
+	}
+
+	c := newCSTParser(src, o)
+	root, err := c.parse()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.p.checkTokenCount(); err != nil {
+		return nil, err
+	}
+	if !c.p.doHaveToken(tokenEOF) {
+		return nil, c.p.unexpectedTokenError("invalid document: document should have only one value", tokenEOF)
+	}
+
+	return &Document{
+		dialect:  o.Dialect,
+		newline:  firstLineBreak(src),
+		root:     root,
+		trailing: string(src[c.end:]),
+	}, nil
+}
+
+// Document is the concrete syntax tree of a JSON document.
+// It keeps whitespaces, comments and the spelling of numbers and strings in the source as they are,
+// so that the untouched regions are printed byte-identically after it is edited.
+type Document struct {
+	dialect Dialect
+	// newline is the line break which the document uses, which the edited regions use as well.
+	newline string
+	root    *cstNode
+	// trailing is the trivia after the root value.
+	trailing string
+}
+
+// Bytes returns the source of the document, which is identical to the parsed one except for the edited regions.
+func (d *Document) Bytes() []byte {
+	var b bytes.Buffer
+	d.root.print(&b)
+	b.WriteString(d.trailing)
+
+	return b.Bytes()
+}
+
+func (d *Document) String() string {
+	return string(d.Bytes())
+}
+
+// Value returns the value which the document represents.
+func (d *Document) Value() (Value, error) {
+	return d.root.value(d.dialect)
+}
+
+// Set replaces the value referenced by the pointer with the new one keeping the trivia around it.
+// A prop is added to the object if the referenced prop does not exist.
+//
+// The new value is written in multiple lines indented in the same way as the document
+// if the value which it replaces or the array or the object which it is added to is written so,
+// and in compact form otherwise.
+func (d *Document) Set(ptr Pointer, val Value) error {
+	if err := d.set(ptr, val); err != nil {
+		return fmt.Errorf("failed to set %s: %w", ptr, err)
+	}
+
+	return nil
+}
+
+func (d *Document) set(ptr Pointer, val Value) error {
+	if len(ptr) == 0 {
+		node, err := d.replacement(d.root, nil, lastLineIndent(d.root.leading, ""), val)
+		if err != nil {
+			return err
+		}
+		d.root = node
+		return nil
+	}
+
+	parent, indent, err := d.container(ptr[:len(ptr)-1])
+	if err != nil {
+		return err
+	}
+	tok, at := ptr[len(ptr)-1], ptr[:len(ptr)-1]
+
+	var i int
+	if parent.kind == KindObject {
+		i = parent.indexOf(tok, d.dialect)
+		if i < 0 {
+			return d.insert(parent, len(parent.elems), &tok, val, indent)
+		}
+	} else {
+		i, err = arrayIndex(tok, len(parent.elems), at, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	elem := parent.elems[i]
+	node, err := d.replacement(elem.val, parent, lastLineIndent(elem.leading(), indent), val)
+	if err != nil {
+		return err
+	}
+	elem.val = node
+
+	return nil
+}
+
+// Insert inserts the new value at the pointer.
+// The value is inserted before the referenced element for arrays, where the token "-" references the end,
+// and added as the last prop for objects, which should not have the prop yet.
+//
+// The new value is written in the same way as Set, and the commas are added following the style of the array or the object.
+func (d *Document) Insert(ptr Pointer, val Value) error {
+	if err := d.insertAt(ptr, val); err != nil {
+		return fmt.Errorf("failed to insert %s: %w", ptr, err)
+	}
+
+	return nil
+}
+
+func (d *Document) insertAt(ptr Pointer, val Value) error {
+	if len(ptr) == 0 {
+		return fmt.Errorf("the whole document cannot be inserted")
+	}
+
+	parent, indent, err := d.container(ptr[:len(ptr)-1])
+	if err != nil {
+		return err
+	}
+	tok, at := ptr[len(ptr)-1], ptr[:len(ptr)-1]
+
+	if parent.kind == KindObject {
+		if parent.indexOf(tok, d.dialect) >= 0 {
+			return fmt.Errorf("%q already exists in object at %q", tok, at)
+		}
+		return d.insert(parent, len(parent.elems), &tok, val, indent)
+	}
+
+	i, err := arrayIndex(tok, len(parent.elems), at, true)
+	if err != nil {
+		return err
+	}
+
+	return d.insert(parent, i, nil, val, indent)
+}
+
+// Delete deletes the value referenced by the pointer with the trivia before it.
+// The comma which separates the value is deleted as well.
+func (d *Document) Delete(ptr Pointer) error {
+	if err := d.delete(ptr); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", ptr, err)
+	}
+
+	return nil
+}
+
+func (d *Document) delete(ptr Pointer) error {
+	if len(ptr) == 0 {
+		return fmt.Errorf("the whole document cannot be deleted")
+	}
+
+	parent, indent, err := d.container(ptr[:len(ptr)-1])
+	if err != nil {
+		return err
+	}
+	tok, at := ptr[len(ptr)-1], ptr[:len(ptr)-1]
+
+	var i int
+	if parent.kind == KindObject {
+		i = parent.indexOf(tok, d.dialect)
+		if i < 0 {
+			return fmt.Errorf("%q is not found in object at %q", tok, at)
+		}
+	} else {
+		i, err = arrayIndex(tok, len(parent.elems), at, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	parent.remove(i, indent, d.newline)
+
+	return nil
+}
+
+// container returns the array or the object referenced by the pointer
+// with the indent of the line where it starts.
+func (d *Document) container(ptr Pointer) (*cstNode, string, error) {
+	curr, indent := d.root, lastLineIndent(d.root.leading, "")
+	for i, tok := range ptr {
+		elem, err := curr.child(tok, ptr[:i], d.dialect)
+		if err != nil {
+			return nil, "", err
+		}
+		curr, indent = elem.val, lastLineIndent(elem.leading(), indent)
+	}
+	if curr.kind != KindArray && curr.kind != KindObject {
+		return nil, "", fmt.Errorf("%s at %q cannot have members", curr.kind, ptr)
+	}
+
+	return curr, indent, nil
+}
+
+// replacement returns the node of the new value which replaces the old one in the parent at the indent.
+// The parent is nil for the root value.
+func (d *Document) replacement(old, parent *cstNode, indent string, val Value) (*cstNode, error) {
+	multiline := parent != nil && parent.isMultiline()
+	if len(old.elems) > 0 {
+		multiline = old.isMultiline()
+	}
+
+	node, err := d.newNode(val, indent, multiline)
+	if err != nil {
+		return nil, err
+	}
+	node.leading = old.leading
+
+	return node, nil
+}
+
+// insert inserts the new value as the i-th member of the parent whose line is indented with the given indent.
+// The key is nil for arrays.
+func (d *Document) insert(parent *cstNode, i int, key *string, val Value, indent string) error {
+	elems := parent.elems
+	var leading string
+	switch {
+	case len(elems) == 0:
+		if strings.Contains(parent.closing, "\n") {
+			leading = d.newline + indent + d.indentUnit()
+		}
+	case i < len(elems):
+		leading = sanitizeLeading(elems[i].leading())
+	default:
+		leading = parent.separator()
+		// The comment after the last member in the same line stays with it following the new comma,
+		// so the new member starts in the next line.
+		if last, end := elems[len(elems)-1], sameLineEnd(parent.closing); !last.hasComma && end < len(parent.closing) && strings.TrimSpace(parent.closing[:end]) != "" {
+			last.afterComma, parent.closing = parent.closing[:end], parent.closing[end:]
+			if !strings.Contains(leading, "\n") {
+				leading = d.newline + indent + d.indentUnit()
+			}
+		}
+	}
+
+	node, err := d.newNode(val, lastLineIndent(leading, indent+d.indentUnit()), parent.isMultiline())
+	if err != nil {
+		return err
+	}
+	elem := &cstElem{
+		val: node,
+	}
+	if key != nil {
+		elem.key = &cstNode{
+			kind: KindString,
+			raw:  quoteString(*key, false, false),
+		}
+		node.leading = " "
+		if len(elems) > 0 {
+			elem.colon = sanitizeInline(elems[0].colon, "")
+			node.leading = sanitizeInline(elems[0].val.leading, " ")
+		}
+	}
+
+	switch {
+	case len(elems) == 0:
+	case i < len(elems):
+		elem.hasComma = true
+		// The next member is separated from the new one in the same way as the other members.
+		if next := elems[i]; !strings.Contains(next.leading(), "\n") {
+			next.setLeading(parent.separator())
+		}
+	default:
+		// The new member follows the trailing comma if the last member has it.
+		if last := elems[len(elems)-1]; last.hasComma {
+			elem.hasComma = true
+		} else {
+			last.hasComma = true
+		}
+	}
+	elem.setLeading(leading)
+
+	parent.elems = append(elems[:i], append([]*cstElem{elem}, elems[i:]...)...)
+
+	return nil
+}
+
+// newNode returns the node of the value written in multiple lines with the indent or in compact form.
+func (d *Document) newNode(val Value, indent string, multiline bool) (*cstNode, error) {
+	var enc Encoder
+	if multiline {
+		enc.prefix, enc.indent = indent, d.indentUnit()
+	}
+
+	var b bytes.Buffer
+	if err := enc.encode(&b, val, 0); err != nil {
+		return nil, err
+	}
+	src := b.Bytes()
+	if d.newline != "\n" {
+		// The encoded value has line breaks only between its members, as the ones in strings are escaped.
+		src = bytes.ReplaceAll(src, []byte("\n"), []byte(d.newline))
+	}
+
+	return newCSTParser(src, ParserOptions{}).parse()
+}
+
+// indentUnit returns the indent which the document uses for each level,
+// which is inferred from the first member written in its own line.
+func (d *Document) indentUnit() string {
+	if unit, ok := d.root.indentUnit(lastLineIndent(d.root.leading, "")); ok {
+		return unit
+	}
+
+	return "  "
+}
+
+// cstNode is a value in the concrete syntax tree.
+type cstNode struct {
+	kind Kind
+	// leading is the trivia such as whitespaces and comments before the value.
+	leading string
+	// raw is the literal of the scalar as it is written in the source.
+	raw string
+	// elems are the elements of the array or the props of the object.
+	elems []*cstElem
+	// closing is the trivia before the ']' or '}' of the array or the object.
+	closing string
+}
+
+func (n *cstNode) print(b *bytes.Buffer) {
+	b.WriteString(n.leading)
+
+	var opening, closing byte
+	switch n.kind {
+	case KindArray:
+		opening, closing = '[', ']'
+	case KindObject:
+		opening, closing = '{', '}'
+	default:
+		b.WriteString(n.raw)
+		return
+	}
+
+	b.WriteByte(opening)
+	for _, elem := range n.elems {
+		if elem.key != nil {
+			elem.key.print(b)
+			b.WriteString(elem.colon)
+			b.WriteByte(':')
+		}
+		elem.val.print(b)
+		if elem.hasComma {
+			b.WriteString(elem.beforeComma)
+			b.WriteByte(',')
+			b.WriteString(elem.afterComma)
+		}
+	}
+	b.WriteString(n.closing)
+	b.WriteByte(closing)
+}
+
+func (n *cstNode) value(dialect Dialect) (Value, error) {
+	switch n.kind {
+	case KindNull:
+		return Null{}, nil
+	case KindBool:
+		return Bool(n.raw == literalTrue), nil
+	case KindNum:
+		lit := n.raw
+		if dialect == DialectJSON5 {
+			var err error
+			lit, err = normalizeJSON5NumLiteral(lit)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return Num{literal: lit}, nil
+	case KindString:
+		s, err := n.stringValue(dialect)
+		if err != nil {
+			return nil, err
+		}
+		return String(s), nil
+	case KindArray:
+		arr := make(Array, len(n.elems))
+		for i, elem := range n.elems {
+			val, err := elem.val.value(dialect)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = val
+		}
+		return arr, nil
+	default:
+		obj := NewObject()
+		for _, elem := range n.elems {
+			key, err := elem.key.stringValue(dialect)
+			if err != nil {
+				return nil, err
+			}
+			val, err := elem.val.value(dialect)
+			if err != nil {
+				return nil, err
+			}
+			obj.append(NewProp(key, val))
+		}
+		return obj, nil
+	}
+}
+
+// stringValue returns the string which the string or the key written as JSON5 identifier represents.
+func (n *cstNode) stringValue(dialect Dialect) (string, error) {
+	if !isStringLiteralQuoted(n.raw) && !isStringLiteralSingleQuoted(n.raw) {
+		return n.raw, nil
+	}
+
+	s, err := unquoteDialectStringLiteral(n.raw, pos{}, dialect)
+	if err != nil {
+		return "", err
+	}
+
+	return s, nil
+}
+
+// child returns the member referenced by the token.
+// The last one wins among the props with the same key in the same way as Pointer.
+func (n *cstNode) child(tok string, at Pointer, dialect Dialect) (*cstElem, error) {
+	switch n.kind {
+	case KindObject:
+		i := n.indexOf(tok, dialect)
+		if i < 0 {
+			return nil, fmt.Errorf("%q is not found in object at %q", tok, at)
+		}
+		return n.elems[i], nil
+	case KindArray:
+		i, err := arrayIndex(tok, len(n.elems), at, false)
+		if err != nil {
+			return nil, err
+		}
+		return n.elems[i], nil
+	default:
+		return nil, fmt.Errorf("%s at %q cannot have %q", n.kind, at, tok)
+	}
+}
+
+func (n *cstNode) indexOf(key string, dialect Dialect) int {
+	for i := len(n.elems) - 1; i >= 0; i-- {
+		if s, err := n.elems[i].key.stringValue(dialect); err == nil && s == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// remove removes the i-th member of the array or the object whose line is indented with the given indent,
+// where the newline is the line break which is added if any.
+func (n *cstNode) remove(i int, indent, newline string) {
+	elems := n.elems
+	elem := elems[i]
+	if i == len(elems)-1 && !elem.hasComma {
+		// The trivia after the last member in the same line, such as the comment for it, is deleted with it.
+		n.closing = n.closing[sameLineEnd(n.closing):]
+	}
+	switch {
+	case len(elems) == 1:
+		if strings.TrimSpace(n.closing) == "" {
+			n.closing = ""
+		}
+	case i == len(elems)-1:
+		// The comma of the previous member is deleted unless it turns into the trailing comma.
+		if prev := elems[i-1]; !elem.hasComma {
+			closing := prev.beforeComma + prev.afterComma
+			if strings.Contains(prev.afterComma, "//") && !strings.HasPrefix(n.closing, "\n") && !strings.HasPrefix(n.closing, "\r\n") {
+				closing += newline + indent
+			}
+			n.closing = closing + n.closing
+			prev.hasComma, prev.beforeComma, prev.afterComma = false, "", ""
+		}
+	case i == 0:
+		if next := elems[1]; !strings.Contains(next.leading(), "\n") {
+			next.setLeading(elem.leading())
+		}
+	}
+
+	n.elems = append(elems[:i], elems[i+1:]...)
+}
+
+// separator returns the trivia which separates the members of the array or the object.
+func (n *cstNode) separator() string {
+	switch {
+	case len(n.elems) > 1:
+		return sanitizeLeading(n.elems[1].leading())
+	case len(n.elems) == 1 && strings.Contains(n.elems[0].leading(), "\n"):
+		return sanitizeLeading(n.elems[0].leading())
+	case len(n.elems) == 1:
+		return " "
+	default:
+		return ""
+	}
+}
+
+// isMultiline reports whether the array or the object is written in multiple lines.
+func (n *cstNode) isMultiline() bool {
+	if strings.Contains(n.closing, "\n") {
+		return true
+	}
+	for _, elem := range n.elems {
+		if strings.Contains(elem.leading(), "\n") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indentUnit reports the indent for each level found first in the node whose line is indented with the given indent.
+func (n *cstNode) indentUnit(indent string) (string, bool) {
+	for _, elem := range n.elems {
+		if strings.Contains(elem.leading(), "\n") {
+			elemIndent := lastLineIndent(elem.leading(), "")
+			if len(elemIndent) > len(indent) && strings.HasPrefix(elemIndent, indent) {
+				return elemIndent[len(indent):], true
+			}
+		}
+		if unit, ok := elem.val.indentUnit(lastLineIndent(elem.leading(), indent)); ok {
+			return unit, true
+		}
+	}
+
+	return "", false
+}
+
+// cstElem is an element of an array or a prop of an object with the ',' which follows it.
+type cstElem struct {
+	// key is the key of the prop, which is nil for the elements of arrays.
+	key *cstNode
+	// colon is the trivia before the ':' of the prop.
+	colon string
+	val   *cstNode
+
+	hasComma bool
+	// beforeComma is the trivia before the ',', and afterComma is the trivia after the ',' in the same line,
+	// such as the comment for the member, only if the next member is in the next line.
+	beforeComma, afterComma string
+}
+
+// leading returns the trivia before the member.
+func (e *cstElem) leading() string {
+	if e.key != nil {
+		return e.key.leading
+	}
+
+	return e.val.leading
+}
+
+func (e *cstElem) setLeading(leading string) {
+	if e.key != nil {
+		e.key.leading = leading
+		return
+	}
+
+	e.val.leading = leading
+}
+
+func newCSTParser(src []byte, opts ParserOptions) *cstParser {
+	return &cstParser{
+		src: src,
+		p:   opts.newParser(bytes.NewReader(src)),
+	}
+}
+
+// cstParser parses the source as the concrete syntax tree with the tokens which its parser reads,
+// where the trivia is the source between the tokens.
+type cstParser struct {
+	src []byte
+	p   *parser
+	// end is the byte offset of the end of the last token which has been read.
+	end int
+}
+
+func (c *cstParser) parse() (*cstNode, error) {
+	if err := c.p.checkTokenCount(); err != nil {
+		return nil, err
+	}
+
+	switch c.p.currTok.kind {
+	case tokenLBracket:
+		return c.parseArray()
+	case tokenLBrace:
+		return c.parseObject()
+	case tokenNum, tokenString, tokenBool, tokenNull:
+		tok, leading := c.p.currTok, c.trivia()
+		// The scalar is parsed only to be validated.
+		val, err := c.p.parse()
+		if err != nil {
+			return nil, err
+		}
+		c.end = tok.endOffset
+		return &cstNode{
+			kind:    val.Kind(),
+			leading: leading,
+			raw:     tok.literal,
+		}, nil
+	default:
+		return nil, c.p.unexpectedTokenError("unknown kind of token", valueTokenKinds...)
+	}
+}
+
+func (c *cstParser) parseArray() (*cstNode, error) {
+	if err := c.p.enter(); err != nil {
+		return nil, err
+	}
+	defer c.p.leave()
+
+	node := &cstNode{
+		kind:    KindArray,
+		leading: c.read(),
+	}
+	if !c.p.doHaveToken(tokenRBracket) {
+		for {
+			val, err := c.parse()
+			if err != nil {
+				return nil, err
+			}
+			elem := &cstElem{
+				val: val,
+			}
+			node.elems = append(node.elems, elem)
+
+			if !c.p.doHaveToken(tokenComma) {
+				break
+			}
+			c.readComma(elem)
+			if c.p.allowsTrailingComma() && c.p.doHaveToken(tokenRBracket) {
+				break
+			}
+		}
+	}
+	if !c.p.doHaveToken(tokenRBracket) {
+		return nil, c.p.unexpectedTokenError("invalid array format: array should end with ']'", tokenComma, tokenRBracket)
+	}
+	node.closing = c.read()
+
+	return node, nil
+}
+
+func (c *cstParser) parseObject() (*cstNode, error) {
+	if err := c.p.enter(); err != nil {
+		return nil, err
+	}
+	defer c.p.leave()
+
+	node := &cstNode{
+		kind:    KindObject,
+		leading: c.read(),
+	}
+	keys := make(map[String]bool)
+	if !c.p.doHaveToken(tokenRBrace) {
+		for {
+			elem, err := c.parseProp(keys)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse prop: %w", err)
+			}
+			node.elems = append(node.elems, elem)
+
+			if !c.p.doHaveToken(tokenComma) {
+				break
+			}
+			c.readComma(elem)
+			if c.p.allowsTrailingComma() && c.p.doHaveToken(tokenRBrace) {
+				break
+			}
+		}
+	}
+	if !c.p.doHaveToken(tokenRBrace) {
+		return nil, c.p.unexpectedTokenError("invalid object format: object should end with '}'", tokenComma, tokenRBrace)
+	}
+	node.closing = c.read()
+
+	return node, nil
+}
+
+func (c *cstParser) parseProp(keys map[String]bool) (*cstElem, error) {
+	keyTok, leading := c.p.currTok, c.trivia()
+	key, _, err := c.p.parseKey()
+	if err != nil {
+		return nil, err
+	}
+	c.end = keyTok.endOffset
+	if keys[key] && c.p.opts.DuplicateKeys == DuplicateKeysError {
+		return nil, c.p.withExcerpt(newSyntaxError(keyTok.pos, "duplicate key in object: %s", keyTok.literal))
+	}
+	keys[key] = true
+
+	if !c.p.doHaveToken(tokenColon) {
+		return nil, c.p.unexpectedTokenError("invalid prop format: prop should be composed of key and value separated by ':'", tokenColon)
+	}
+	colon := c.read()
+
+	val, err := c.parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse value: %w", err)
+	}
+
+	return &cstElem{
+		key: &cstNode{
+			kind:    KindString,
+			leading: leading,
+			raw:     keyTok.literal,
+		},
+		colon: colon,
+		val:   val,
+	}, nil
+}
+
+// read reads the current token returning the trivia before it.
+func (c *cstParser) read() string {
+	trivia := c.trivia()
+	c.end = c.p.currTok.endOffset
+	c.p.readToken()
+
+	return trivia
+}
+
+// readComma reads the ',' after the member, which takes the trivia after it in the same line.
+func (c *cstParser) readComma(elem *cstElem) {
+	elem.hasComma = true
+	elem.beforeComma = c.read()
+
+	// The trivia is taken only if the next member is in the next line,
+	// otherwise it is the one which separates the members in the line.
+	trivia := c.trivia()
+	if i := sameLineEnd(trivia); i < len(trivia) {
+		elem.afterComma = trivia[:i]
+		c.end += i
+	}
+}
+
+// trivia returns the source between the last token and the current one.
+func (c *cstParser) trivia() string {
+	return string(c.src[c.end:c.p.currTok.pos.offset])
+}
+
+// sameLineEnd returns the index of the first line break in the trivia which is not in block comments,
+// or the length of the trivia if there is no such line break.
+// The line break starts at the '\r' of "\r\n".
+func sameLineEnd(trivia string) int {
+	for i := 0; i < len(trivia); i++ {
+		switch {
+		case trivia[i] == '\n':
+			return lineBreakStart(trivia, i)
+		case strings.HasPrefix(trivia[i:], "//"):
+			// The line comment ends at the line break even if it has "/*" in it.
+			end := strings.IndexByte(trivia[i:], '\n')
+			if end < 0 {
+				return len(trivia)
+			}
+			return lineBreakStart(trivia, i+end)
+		case strings.HasPrefix(trivia[i:], "/*"):
+			end := strings.Index(trivia[i+2:], "*/")
+			if end < 0 {
+				return len(trivia)
+			}
+			i += 2 + end + 1
+		}
+	}
+
+	return len(trivia)
+}
+
+// lineBreakStart returns the index where the line break whose '\n' is at the given index starts.
+func lineBreakStart(trivia string, i int) int {
+	if i > 0 && trivia[i-1] == '\r' {
+		return i - 1
+	}
+
+	return i
+}
+
+// firstLineBreak returns the first line break in the source, which is either "\r\n" or "\n",
+// or "\n" if the source has no line break.
+func firstLineBreak(src []byte) string {
+	if i := bytes.IndexByte(src, '\n'); i > 0 && src[i-1] == '\r' {
+		return "\r\n"
+	}
+
+	return "\n"
+}
+
+// lastLineIndent returns the whitespaces at the beginning of the last line of the trivia,
+// or the fallback if the trivia has no line break.
+func lastLineIndent(trivia, fallback string) string {
+	i := strings.LastIndexByte(trivia, '\n')
+	if i < 0 {
+		return fallback
+	}
+	line := trivia[i+1:]
+
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// sanitizeLeading returns the trivia without comments which puts the member in the same place as the given one does.
+func sanitizeLeading(trivia string) string {
+	if i := strings.LastIndexByte(trivia, '\n'); i >= 0 {
+		if i > 0 && trivia[i-1] == '\r' {
+			return "\r\n" + lastLineIndent(trivia, "")
+		}
+		return "\n" + lastLineIndent(trivia, "")
+	}
+
+	return sanitizeInline(trivia, "")
+}
+
+// sanitizeInline returns the trivia as it is if it consists only of spaces and tabs, or the fallback otherwise.
+func sanitizeInline(trivia, fallback string) string {
+	if strings.Trim(trivia, " \t") != "" {
+		return fallback
+	}
+
+	return trivia
+}
@@ -0,0 +1,499 @@
+package json
+
+import (
+	"testing"
+)
+
+func TestParseDocument(t *testing.T) {
+	tests := map[string]struct {
+		dialect Dialect
+		src     string
+	}{
+		"scalar": {
+			src: " 1.0e3 \n",
+		},
+		"compact": {
+			src: `{"a":[1,2.50,{"b":null}],"c":"A\n"}`,
+		},
+		"pretty": {
+			src: "{\n\t\"a\": [\n\t\t1,\n\t\ttrue\n\t],\n\t\"b\" : { }\n}\n",
+		},
+		"duplicate keys": {
+			src: `{"a": 1, "a": 2}`,
+		},
+		"comments": {
+			dialect: DialectJSONC,
+			src: `// config
+{
+  /* name */ "name": "a", // the name
+  "tags": [ "b", /* multi
+  line */ "c", ], // the tags
+}
+`,
+		},
+		"json5": {
+			dialect: DialectJSON5,
+			src:     "{unquoted: 'single', hex: 0xFF, plus: +.5, nan: NaN,} // done",
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			opts := ParserOptions{Dialect: test.dialect}
+			doc, err := opts.ParseDocument([]byte(test.src))
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+			if actual := doc.String(); actual != test.src {
+				t.Errorf("should have printed the source as it is: %s", reportUnexpected("source", actual, test.src))
+				return
+			}
+
+			actual, err := doc.Value()
+			if err != nil {
+				t.Errorf("should have returned the value: %s", err)
+				return
+			}
+			expected, err := opts.Parse([]byte(test.src))
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+			if err := assertValue(actual, expected); err != nil {
+				t.Errorf("should have returned the value which the source represents: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestParseDocumentFails(t *testing.T) {
+	tests := map[string]struct {
+		opts ParserOptions
+		src  string
+	}{
+		"empty": {
+			src: ``,
+		},
+		"trailing data": {
+			src: `{} []`,
+		},
+		"invalid value": {
+			src: `[1, tru]`,
+		},
+		"trailing comma": {
+			src: `[1,]`,
+		},
+		"comment": {
+			src: `[1] // comment`,
+		},
+		"duplicate keys": {
+			opts: ParserOptions{DuplicateKeys: DuplicateKeysError},
+			src:  `{"a": 1, "a": 2}`,
+		},
+		"too deep": {
+			opts: ParserOptions{MaxDepth: 1},
+			src:  `[[]]`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			if _, err := test.opts.ParseDocument([]byte(test.src)); err == nil {
+				t.Errorf("should have failed to parse: %s", test.src)
+				return
+			}
+		})
+	}
+}
+
+func TestDocumentSet(t *testing.T) {
+	tests := map[string]struct {
+		dialect  Dialect
+		src      string
+		ptr      string
+		val      Value
+		expected string
+	}{
+		"scalar in compact": {
+			src:      `{"a":1.0,"b":[true,"x"]}`,
+			ptr:      "/b/1",
+			val:      String("y"),
+			expected: `{"a":1.0,"b":[true,"y"]}`,
+		},
+		"object in pretty": {
+			src:      "{\n  \"a\": 1,\n  \"b\": 2\n}\n",
+			ptr:      "/a",
+			val:      NewObject(NewProp("c", Array{Num{literal: "3"}})),
+			expected: "{\n  \"a\": {\n    \"c\": [\n      3\n    ]\n  },\n  \"b\": 2\n}\n",
+		},
+		"inline array in pretty": {
+			src:      "{\n  \"a\": [1, 2]\n}",
+			ptr:      "/a",
+			val:      Array{Num{literal: "3"}, Num{literal: "4"}},
+			expected: "{\n  \"a\": [3,4]\n}",
+		},
+		"new prop": {
+			src:      "{\n    \"a\": 1\n}",
+			ptr:      "/b",
+			val:      Bool(false),
+			expected: "{\n    \"a\": 1,\n    \"b\": false\n}",
+		},
+		"new prop in crlf": {
+			src:      "{\r\n  \"a\": 1\r\n}",
+			ptr:      "/b",
+			val:      Null{},
+			expected: "{\r\n  \"a\": 1,\r\n  \"b\": null\r\n}",
+		},
+		"object in crlf": {
+			src:      "{\r\n  \"a\": 1\r\n}",
+			ptr:      "/a",
+			val:      NewObject(NewProp("b", Array{Num{literal: "2"}})),
+			expected: "{\r\n  \"a\": {\r\n    \"b\": [\r\n      2\r\n    ]\r\n  }\r\n}",
+		},
+		"new prop after comment": {
+			dialect:  DialectJSONC,
+			src:      "{\n  \"a\": 1 // one\n}",
+			ptr:      "/b",
+			val:      Num{literal: "2"},
+			expected: "{\n  \"a\": 1, // one\n  \"b\": 2\n}",
+		},
+		"new prop in empty object": {
+			src:      `{"a": {}}`,
+			ptr:      "/a/b",
+			val:      Null{},
+			expected: `{"a": {"b": null}}`,
+		},
+		"new prop after trailing comma": {
+			dialect:  DialectJSONC,
+			src:      "{\n\t\"a\": 1, // one\n}",
+			ptr:      "/b",
+			val:      Num{literal: "2"},
+			expected: "{\n\t\"a\": 1, // one\n\t\"b\": 2,\n}",
+		},
+		"keeps comments": {
+			dialect:  DialectJSONC,
+			src:      "{\n  // the value\n  \"a\": /* old */ 1 // one\n}",
+			ptr:      "/a",
+			val:      Num{literal: "2"},
+			expected: "{\n  // the value\n  \"a\": /* old */ 2 // one\n}",
+		},
+		"json5 identifier key": {
+			dialect:  DialectJSON5,
+			src:      "{a: 'x', b: 0x10}",
+			ptr:      "/b",
+			val:      String("it's"),
+			expected: "{a: 'x', b: \"it's\"}",
+		},
+		"last of duplicate keys": {
+			src:      `{"a": 1, "a": 2}`,
+			ptr:      "/a",
+			val:      Num{literal: "3"},
+			expected: `{"a": 1, "a": 3}`,
+		},
+		"root": {
+			src:      " [1] \n",
+			ptr:      "",
+			val:      NewObject(),
+			expected: " {} \n",
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			doc, err := ParserOptions{Dialect: test.dialect}.ParseDocument([]byte(test.src))
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+			ptr, err := ParsePointer(test.ptr)
+			if err != nil {
+				t.Errorf("should have parsed the pointer: %s", err)
+				return
+			}
+
+			if err := doc.Set(ptr, test.val); err != nil {
+				t.Errorf("should have set: %s", err)
+				return
+			}
+			if actual := doc.String(); actual != test.expected {
+				t.Errorf("should have set the value: %s", reportUnexpected("source", actual, test.expected))
+				return
+			}
+			assertDocumentValue(t, doc)
+		})
+	}
+}
+
+func TestDocumentInsert(t *testing.T) {
+	tests := map[string]struct {
+		dialect  Dialect
+		src      string
+		ptr      string
+		val      Value
+		expected string
+	}{
+		"first element": {
+			src:      `[1, 2]`,
+			ptr:      "/0",
+			val:      Num{literal: "0"},
+			expected: `[0, 1, 2]`,
+		},
+		"middle element": {
+			src:      `[1,2]`,
+			ptr:      "/1",
+			val:      Num{literal: "9"},
+			expected: `[1,9,2]`,
+		},
+		"last element": {
+			src:      `[1]`,
+			ptr:      "/-",
+			val:      Num{literal: "2"},
+			expected: `[1, 2]`,
+		},
+		"element in empty array": {
+			src:      `[ ]`,
+			ptr:      "/-",
+			val:      Num{literal: "1"},
+			expected: `[1 ]`,
+		},
+		"element in empty array in pretty": {
+			src:      "{\n  \"a\": [\n  ]\n}",
+			ptr:      "/a/0",
+			val:      Array{Null{}},
+			expected: "{\n  \"a\": [\n    [\n      null\n    ]\n  ]\n}",
+		},
+		"first element before comment": {
+			dialect:  DialectJSONC,
+			src:      "[\n  // one\n  1\n]",
+			ptr:      "/0",
+			val:      Num{literal: "0"},
+			expected: "[\n  0,\n  // one\n  1\n]",
+		},
+		"last element after comment": {
+			dialect:  DialectJSONC,
+			src:      "[1 // one\n]",
+			ptr:      "/-",
+			val:      Null{},
+			expected: "[1, // one\n  null\n]",
+		},
+		"last element after comment in crlf": {
+			dialect:  DialectJSONC,
+			src:      "[\r\n  1 // one\r\n]",
+			ptr:      "/-",
+			val:      Null{},
+			expected: "[\r\n  1, // one\r\n  null\r\n]",
+		},
+		"element in empty array in crlf": {
+			src:      "{\r\n  \"a\": [\r\n  ]\r\n}",
+			ptr:      "/a/0",
+			val:      Num{literal: "1"},
+			expected: "{\r\n  \"a\": [\r\n    1\r\n  ]\r\n}",
+		},
+		"prop": {
+			src:      `{"a":1}`,
+			ptr:      "/b~1c",
+			val:      String("d"),
+			expected: `{"a":1, "b/c":"d"}`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			doc, err := ParserOptions{Dialect: test.dialect}.ParseDocument([]byte(test.src))
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+			ptr, err := ParsePointer(test.ptr)
+			if err != nil {
+				t.Errorf("should have parsed the pointer: %s", err)
+				return
+			}
+
+			if err := doc.Insert(ptr, test.val); err != nil {
+				t.Errorf("should have inserted: %s", err)
+				return
+			}
+			if actual := doc.String(); actual != test.expected {
+				t.Errorf("should have inserted the value: %s", reportUnexpected("source", actual, test.expected))
+				return
+			}
+			assertDocumentValue(t, doc)
+		})
+	}
+}
+
+func TestDocumentDelete(t *testing.T) {
+	tests := map[string]struct {
+		dialect  Dialect
+		src      string
+		ptr      string
+		expected string
+	}{
+		"first element": {
+			src:      `[1, 2, 3]`,
+			ptr:      "/0",
+			expected: `[2, 3]`,
+		},
+		"middle element": {
+			src:      `[1, 2, 3]`,
+			ptr:      "/1",
+			expected: `[1, 3]`,
+		},
+		"last element": {
+			src:      `[1, 2, 3]`,
+			ptr:      "/2",
+			expected: `[1, 2]`,
+		},
+		"only element": {
+			src:      "{\"a\": [\n  1\n]}",
+			ptr:      "/a/0",
+			expected: `{"a": []}`,
+		},
+		"prop in pretty": {
+			src:      "{\n  \"a\": 1,\n  \"b\": 2,\n  \"c\": 3\n}\n",
+			ptr:      "/b",
+			expected: "{\n  \"a\": 1,\n  \"c\": 3\n}\n",
+		},
+		"last prop in pretty": {
+			src:      "{\n  \"a\": 1,\n  \"b\": 2\n}",
+			ptr:      "/b",
+			expected: "{\n  \"a\": 1\n}",
+		},
+		"last prop with trailing comma": {
+			dialect:  DialectJSONC,
+			src:      "{\n  \"a\": 1,\n  \"b\": 2, // two\n}",
+			ptr:      "/b",
+			expected: "{\n  \"a\": 1,\n}",
+		},
+		"prop after line comment with block comment opener": {
+			dialect:  DialectJSONC,
+			src:      "{\n  \"a\": 1, // see /* x\n  \"b\": 2\n}",
+			ptr:      "/b",
+			expected: "{\n  \"a\": 1 // see /* x\n}",
+		},
+		"last element with comment": {
+			dialect:  DialectJSONC,
+			src:      "[\n  1, // c1\n  2 // c2\n]",
+			ptr:      "/1",
+			expected: "[\n  1 // c1\n]",
+		},
+		"only element with comment": {
+			dialect:  DialectJSONC,
+			src:      "[\n  1 // c1\n]",
+			ptr:      "/0",
+			expected: "[]",
+		},
+		"last element after comment": {
+			dialect:  DialectJSONC,
+			src:      "[1, // one\n2]",
+			ptr:      "/1",
+			expected: "[1 // one\n]",
+		},
+		"last element with comment in crlf": {
+			dialect:  DialectJSONC,
+			src:      "[\r\n  1, // c1\r\n  2 // c2\r\n]",
+			ptr:      "/1",
+			expected: "[\r\n  1 // c1\r\n]",
+		},
+		"last element after comment in crlf": {
+			dialect:  DialectJSONC,
+			src:      "[1, // one\r\n2]",
+			ptr:      "/1",
+			expected: "[1 // one\r\n]",
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			doc, err := ParserOptions{Dialect: test.dialect}.ParseDocument([]byte(test.src))
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+			ptr, err := ParsePointer(test.ptr)
+			if err != nil {
+				t.Errorf("should have parsed the pointer: %s", err)
+				return
+			}
+
+			if err := doc.Delete(ptr); err != nil {
+				t.Errorf("should have deleted: %s", err)
+				return
+			}
+			if actual := doc.String(); actual != test.expected {
+				t.Errorf("should have deleted the value: %s", reportUnexpected("source", actual, test.expected))
+				return
+			}
+			assertDocumentValue(t, doc)
+		})
+	}
+}
+
+func TestDocumentEditFails(t *testing.T) {
+	tests := map[string]func(doc *Document) error{
+		"set in scalar": func(doc *Document) error {
+			return doc.Set(Pointer{"a", "b"}, Null{})
+		},
+		"set out of range": func(doc *Document) error {
+			return doc.Set(Pointer{"c", "1"}, Null{})
+		},
+		"insert existing prop": func(doc *Document) error {
+			return doc.Insert(Pointer{"a"}, Null{})
+		},
+		"insert root": func(doc *Document) error {
+			return doc.Insert(Pointer{}, Null{})
+		},
+		"delete missing prop": func(doc *Document) error {
+			return doc.Delete(Pointer{"z"})
+		},
+		"delete root": func(doc *Document) error {
+			return doc.Delete(Pointer{})
+		},
+		"set non-finite number": func(doc *Document) error {
+			return doc.Set(Pointer{"a"}, Num{literal: literalNaN})
+		},
+	}
+
+	src := `{"a": 1, "c": [true]}`
+	for n, edit := range tests {
+		t.Run(n, func(t *testing.T) {
+			doc, err := ParseDocument([]byte(src))
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+
+			if err := edit(doc); err == nil {
+				t.Errorf("should have failed to edit")
+				return
+			}
+			if actual := doc.String(); actual != src {
+				t.Errorf("should not have changed the document: %s", reportUnexpected("source", actual, src))
+				return
+			}
+		})
+	}
+}
+
+// assertDocumentValue asserts that the edited document is still valid and represents the same value as the parsed source.
+func assertDocumentValue(t *testing.T, doc *Document) {
+	t.Helper()
+
+	actual, err := doc.Value()
+	if err != nil {
+		t.Errorf("should have returned the value: %s", err)
+		return
+	}
+	expected, err := ParserOptions{Dialect: doc.dialect}.Parse(doc.Bytes())
+	if err != nil {
+		t.Errorf("should have printed the valid source: %s", err)
+		return
+	}
+	if err := assertValue(actual, expected); err != nil {
+		t.Errorf("should have returned the value which the printed source represents: %s", err)
+		return
+	}
+}
@@ -0,0 +1,169 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+)
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w: w,
+	}
+}
+
+// Encoder writes JSON values to its writer.
+// It writes values in compact form unless its indent is set.
+type Encoder struct {
+	w              io.Writer
+	prefix, indent string
+	sortKeys       bool
+	escapeHTML     bool
+	asciiOnly      bool
+}
+
+// SetIndent makes the encoder write each element of arrays and objects
+// in a new line which begins with the prefix followed by the indent as many as the depth.
+func (e *Encoder) SetIndent(prefix, indent string) {
+	e.prefix, e.indent = prefix, indent
+}
+
+// SetSortKeys makes the encoder write props of objects in the order of their keys.
+func (e *Encoder) SetSortKeys(sort bool) {
+	e.sortKeys = sort
+}
+
+// SetEscapeHTML makes the encoder escape '<', '>' and '&' in strings
+// so that the output can be embedded in HTML safely.
+func (e *Encoder) SetEscapeHTML(escape bool) {
+	e.escapeHTML = escape
+}
+
+// SetASCIIOnly makes the encoder escape non ASCII characters in strings as \uXXXX.
+func (e *Encoder) SetASCIIOnly(ascii bool) {
+	e.asciiOnly = ascii
+}
+
+// Encode writes the given value followed by a newline.
+func (e *Encoder) Encode(v Value) error {
+	var buf bytes.Buffer
+	if err := e.encode(&buf, v, 0); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+
+	if _, err := e.w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write: %w", err)
+	}
+
+	return nil
+}
+
+func (e Encoder) encode(buf *bytes.Buffer, v Value, depth int) error {
+	switch v := v.(type) {
+	case Null:
+		buf.WriteString(literalNull)
+	case Bool:
+		if v {
+			buf.WriteString(literalTrue)
+		} else {
+			buf.WriteString(literalFalse)
+		}
+	case Num:
+		if v.literal == "" {
+			return fmt.Errorf("zero value of Num cannot be encoded as JSON")
+		}
+		if isNonFiniteNumLiteral(v.literal) {
+			return fmt.Errorf("%s cannot be encoded as JSON", v.literal)
+		}
+		buf.WriteString(v.literal)
+	case String:
+		buf.WriteString(quoteString(string(v), e.escapeHTML, e.asciiOnly))
+	case Array:
+		return e.encodeArray(buf, v, depth)
+	case Object:
+		return e.encodeObject(buf, v, depth)
+	default:
+		return fmt.Errorf("unknown type of value: %T", v)
+	}
+
+	return nil
+}
+
+func (e Encoder) encodeArray(buf *bytes.Buffer, arr Array, depth int) error {
+	buf.WriteByte('[')
+	if len(arr) == 0 {
+		buf.WriteByte(']')
+		return nil
+	}
+
+	for i, elem := range arr {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		e.writeNewline(buf, depth+1)
+
+		if err := e.encode(buf, elem, depth+1); err != nil {
+			return fmt.Errorf("failed to encode value at %d: %w", i, err)
+		}
+	}
+
+	e.writeNewline(buf, depth)
+	buf.WriteByte(']')
+
+	return nil
+}
+
+func (e Encoder) encodeObject(buf *bytes.Buffer, obj Object, depth int) error {
+	buf.WriteByte('{')
+	if obj.Len() == 0 {
+		buf.WriteByte('}')
+		return nil
+	}
+
+	props := obj.Props()
+	if e.sortKeys {
+		sort.SliceStable(props, func(i, j int) bool {
+			return props[i].key < props[j].key
+		})
+	}
+
+	for i, prop := range props {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		e.writeNewline(buf, depth+1)
+
+		buf.WriteString(quoteString(string(prop.key), e.escapeHTML, e.asciiOnly))
+		buf.WriteByte(':')
+		if e.isPretty() {
+			buf.WriteByte(' ')
+		}
+
+		if err := e.encode(buf, prop.val, depth+1); err != nil {
+			return fmt.Errorf("failed to encode value of %s: %w", prop.key, err)
+		}
+	}
+
+	e.writeNewline(buf, depth)
+	buf.WriteByte('}')
+
+	return nil
+}
+
+func (e Encoder) writeNewline(buf *bytes.Buffer, depth int) {
+	if !e.isPretty() {
+		return
+	}
+
+	buf.WriteByte('\n')
+	buf.WriteString(e.prefix)
+	for i := 0; i < depth; i++ {
+		buf.WriteString(e.indent)
+	}
+}
+
+func (e Encoder) isPretty() bool {
+	return e.prefix != "" || e.indent != ""
+}
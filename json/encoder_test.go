@@ -0,0 +1,163 @@
+package json
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	val := NewObject(
+		Prop{key: "b", val: Array{Num{literal: "1"}, String("two"), Bool(true), Null{}}},
+		Prop{key: "a", val: NewObject(
+			Prop{key: "c", val: Array{}},
+			Prop{key: "d", val: NewObject()},
+		)},
+	)
+
+	tests := map[string]struct {
+		val      Value
+		setUp    func(*Encoder)
+		expected string
+	}{
+		"compact": {
+			val:      val,
+			setUp:    func(*Encoder) {},
+			expected: `{"b":[1,"two",true,null],"a":{"c":[],"d":{}}}`,
+		},
+		"pretty": {
+			val: val,
+			setUp: func(e *Encoder) {
+				e.SetIndent("", "  ")
+			},
+			expected: `{
+  "b": [
+    1,
+    "two",
+    true,
+    null
+  ],
+  "a": {
+    "c": [],
+    "d": {}
+  }
+}`,
+		},
+		"pretty with prefix": {
+			val: Array{Num{literal: "1"}, Array{Num{literal: "2"}}},
+			setUp: func(e *Encoder) {
+				e.SetIndent("//", "\t")
+			},
+			expected: "[\n//\t1,\n//\t[\n//\t\t2\n//\t]\n//]",
+		},
+		"sorted keys": {
+			val: val,
+			setUp: func(e *Encoder) {
+				e.SetSortKeys(true)
+			},
+			expected: `{"a":{"c":[],"d":{}},"b":[1,"two",true,null]}`,
+		},
+		"escaped string": {
+			val:      String("\"\\/\b\f\n\r\t\x01<>&\u2028あ😀"),
+			setUp:    func(*Encoder) {},
+			expected: `"\"\\/\b\f\n\r\t\u0001<>&` + "\u2028あ😀\"",
+		},
+		"html safe string": {
+			val: String("<a href=\"x\">&</a>\u2028"),
+			setUp: func(e *Encoder) {
+				e.SetEscapeHTML(true)
+			},
+			expected: `"\u003ca href=\"x\"\u003e\u0026\u003c/a\u003e\u2028"`,
+		},
+		"ascii only string": {
+			val: String("aあ😀"),
+			setUp: func(e *Encoder) {
+				e.SetASCIIOnly(true)
+			},
+			expected: `"a\u3042\ud83d\ude00"`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			var b bytes.Buffer
+			enc := NewEncoder(&b)
+			test.setUp(enc)
+
+			if err := enc.Encode(test.val); err != nil {
+				t.Errorf("should have encoded: %s", err)
+				return
+			}
+			if actual := b.String(); actual != test.expected+"\n" {
+				t.Errorf("should have encoded: %s", reportUnexpected("output", actual, test.expected+"\n"))
+				return
+			}
+		})
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	src := `{"a": [1, -2.5e10, "th\"ree", true, false, null], "b": {"c": {}, "d": [], "e": "あ😀<>&"}}`
+	setUps := map[string]func(*Encoder){
+		"compact": func(*Encoder) {},
+		"pretty": func(e *Encoder) {
+			e.SetIndent("\t", "    ")
+		},
+		"html safe and ascii only": func(e *Encoder) {
+			e.SetEscapeHTML(true)
+			e.SetASCIIOnly(true)
+		},
+	}
+
+	expected, err := newTestParser(src).parse()
+	if err != nil {
+		t.Fatalf("failed to parse source: %s", err)
+	}
+
+	for n, setUp := range setUps {
+		t.Run(n, func(t *testing.T) {
+			var b bytes.Buffer
+			enc := NewEncoder(&b)
+			setUp(enc)
+
+			if err := enc.Encode(expected); err != nil {
+				t.Errorf("should have encoded: %s", err)
+				return
+			}
+
+			actual, err := newTestParser(b.String()).parse()
+			if err != nil {
+				t.Errorf("should have parsed encoded: %s", err)
+				return
+			}
+			if err := assertValue(actual, expected); err != nil {
+				t.Errorf("should have given back equal value: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func newTestParser(src string) *parser {
+	p := newParser(newLexer(strings.NewReader(src)))
+	return &p
+}
+
+func TestEncodeFails(t *testing.T) {
+	tests := map[string]Value{
+		"zero num":     Num{},
+		"zero num in":  NewObject(NewProp("a", Array{Num{}})),
+		"non-finite":   Num{literal: "NaN"},
+		"unknown type": nil,
+	}
+
+	for n, val := range tests {
+		t.Run(n, func(t *testing.T) {
+			if err := NewEncoder(io.Discard).Encode(val); err == nil {
+				t.Errorf("should have failed to encode: %v", val)
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package json
+
+// equalValues reports whether the given values are equal as JSON values.
+// Numbers are compared numerically, and props of objects are compared regardless of their order.
+func equalValues(a, b Value) bool {
+	switch a := a.(type) {
+	case Null:
+		_, ok := b.(Null)
+		return ok
+	case Bool:
+		b, ok := b.(Bool)
+		return ok && a == b
+	case Num:
+		b, ok := b.(Num)
+		return ok && a.cmp(b) == 0
+	case String:
+		b, ok := b.(String)
+		return ok && a == b
+	case Array:
+		b, ok := b.(Array)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalValues(a[i], b[i]) {
+				return false
+			}
+		}
+
+		return true
+	case Object:
+		b, ok := b.(Object)
+		return ok && containsProps(a, b) && containsProps(b, a)
+	default:
+		return false
+	}
+}
+
+// containsProps reports whether b has all the props of a with the equal values.
+func containsProps(a, b Object) bool {
+	for _, prop := range a.effectiveProps() {
+		val, ok := b.Get(string(prop.key))
+		if !ok || !equalValues(prop.val, val) {
+			return false
+		}
+	}
+
+	return true
+}
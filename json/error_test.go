@@ -0,0 +1,130 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSyntaxError(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected SyntaxError
+	}{
+		"unclosed array": {
+			src: `[1, 2}`,
+			expected: SyntaxError{
+				Line: 1, Column: 6, Offset: 5,
+				Expected: []string{",", "]"},
+				Found:    "}",
+				Excerpt:  "[1, 2}\n     ^",
+			},
+		},
+		"missing colon in nested object": {
+			src: "{\n\t\"a\": {\"b\" 1}\n}",
+			expected: SyntaxError{
+				Line: 2, Column: 12, Offset: 13,
+				Expected: []string{":"},
+				Found:    "number",
+				Excerpt:  "\t\"a\": {\"b\" 1}\n\t          ^",
+			},
+		},
+		"non string key": {
+			src: `{1: 2}`,
+			expected: SyntaxError{
+				Line: 1, Column: 2, Offset: 1,
+				Expected: []string{"string"},
+				Found:    "number",
+				Excerpt:  "{1: 2}\n ^",
+			},
+		},
+		"invalid escape after multibyte character": {
+			src: `["あ\x"]`,
+			expected: SyntaxError{
+				Line: 1, Column: 4, Offset: 5,
+				Excerpt: "[\"あ\\x\"]\n   ^",
+			},
+		},
+		"leading zero": {
+			src: `[00]`,
+			expected: SyntaxError{
+				Line: 1, Column: 2, Offset: 1,
+				Excerpt: "[00]\n ^",
+			},
+		},
+		"error on line before next token": {
+			src: "[1 \"x\"\n\n]",
+			expected: SyntaxError{
+				Line: 1, Column: 4, Offset: 3,
+				Expected: []string{",", "]"},
+				Found:    "string",
+				Excerpt:  "[1 \"x\"\n   ^",
+			},
+		},
+		"unexpected EOF": {
+			src: "[1,",
+			expected: SyntaxError{
+				Line: 1, Column: 4, Offset: 3,
+				Expected: []string{"[", "{", "number", "string", "bool", "null"},
+				Found:    "EOF",
+				Excerpt:  "[1,\n   ^",
+			},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			lex := newLexer(strings.NewReader(test.src))
+			p := newParser(lex)
+
+			_, err := p.parse()
+			var actual *SyntaxError
+			if !errors.As(err, &actual) {
+				t.Errorf("should have returned SyntaxError: %v", err)
+				return
+			}
+			if err := assertSyntaxError(*actual, test.expected); err != nil {
+				t.Errorf("should have returned SyntaxError: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func assertSyntaxError(actual, expected SyntaxError) error {
+	if actual.Line != expected.Line {
+		return reportUnexpected("line", actual.Line, expected.Line)
+	}
+	if actual.Column != expected.Column {
+		return reportUnexpected("column", actual.Column, expected.Column)
+	}
+	if actual.Offset != expected.Offset {
+		return reportUnexpected("offset", actual.Offset, expected.Offset)
+	}
+	if fmt.Sprint(actual.Expected) != fmt.Sprint(expected.Expected) {
+		return reportUnexpected("expected", actual.Expected, expected.Expected)
+	}
+	if actual.Found != expected.Found {
+		return reportUnexpected("found", actual.Found, expected.Found)
+	}
+	if actual.Excerpt != expected.Excerpt {
+		return reportUnexpected("excerpt", fmt.Sprintf("%q", actual.Excerpt), fmt.Sprintf("%q", expected.Excerpt))
+	}
+
+	return nil
+}
+
+func TestSyntaxErrorMessage(t *testing.T) {
+	err := &SyntaxError{
+		Msg:  "invalid array format",
+		Line: 1, Column: 6,
+		Expected: []string{",", "]"},
+		Found:    "}",
+	}
+	expected := "syntax error at line 1, column 6: invalid array format: expected ',' or ']', but found '}'"
+	if actual := err.Error(); actual != expected {
+		t.Errorf("should have returned message: %s", reportUnexpected("message", actual, expected))
+		return
+	}
+}
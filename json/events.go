@@ -0,0 +1,253 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Handler handles the events which ParseEvents emits while it parses the source,
+// where each position is the one of the first character of the token for the event.
+//
+// The handler can return ErrStop to stop parsing or ErrSkip to skip the array or the object which is starting,
+// and any other error stops parsing with the error.
+type Handler interface {
+	StartObject(at Position) error
+	// Key is called with the key of each prop before the events for its value.
+	Key(key string, at Position) error
+	EndObject(at Position) error
+	StartArray(at Position) error
+	EndArray(at Position) error
+	// Scalar is called with each Null, Bool, Num and String which are not keys.
+	Scalar(val Value, at Position) error
+}
+
+var (
+	// ErrStop stops ParseEvents without any error when it is returned by Handler.
+	ErrStop = errors.New("stop parsing")
+	// ErrSkip skips the array or the object without emitting the events for it any more
+	// when it is returned by StartArray or StartObject, including the one for its end.
+	// It skips the value of the prop when it is returned by Key, and means nothing for the other events.
+	ErrSkip = errors.New("skip value")
+)
+
+// Position is the position in the source, where Line and Column are 1-based.
+type Position struct {
+	Line, Column, Offset int
+}
+
+func newPosition(at pos) Position {
+	return Position{
+		Line:   at.line + 1,
+		Column: at.start + 1,
+		Offset: at.offset,
+	}
+}
+
+// ParseEvents parses the source with the default options calling the handler for each event.
+func ParseEvents(r io.Reader, h Handler) error {
+	return ParserOptions{}.ParseEvents(r, h)
+}
+
+// ParseEvents parses the source calling the handler for each event rather than building the whole value,
+// which keeps the memory use low however large the source is.
+// The events are emitted as soon as the tokens are read, so the handler may have received events
+// before the error is returned for the invalid source.
+//
+// The skipped values are validated in the same way as the others,
+// and DuplicateKeys is not applied as the keys are not kept.
+func (o ParserOptions) ParseEvents(r io.Reader, h Handler) error {
+	if o.MaxDocumentSize > 0 {
+		r = &sizeLimitedReader{
+			r:         r,
+			max:       o.MaxDocumentSize,
+			remaining: o.MaxDocumentSize,
+		}
+	}
+
+	p := o.newParser(r)
+	err := p.parseEventsDocument(h)
+	if p.lex.err != nil {
+		return fmt.Errorf("failed to read: %w", p.lex.err)
+	}
+	if errors.Is(err, ErrStop) {
+		return nil
+	}
+
+	return err
+}
+
+func (p *parser) parseEventsDocument(h Handler) error {
+	if err := p.parseEvents(h); err != nil {
+		return err
+	}
+
+	if err := p.checkTokenCount(); err != nil {
+		return err
+	}
+	if p.opts.DisallowTrailingData && !p.doHaveToken(tokenEOF) {
+		return p.unexpectedTokenError("invalid document: document should have only one value", tokenEOF)
+	}
+
+	return nil
+}
+
+// parseEvents parses the value which starts with the current token calling the handler for each event.
+func (p *parser) parseEvents(h Handler) error {
+	if err := p.checkTokenCount(); err != nil {
+		return err
+	}
+
+	at := newPosition(p.currTok.pos)
+	var val Value
+	var err error
+	switch p.currTok.kind {
+	case tokenLBracket:
+		return p.parseArrayEvents(h)
+	case tokenLBrace:
+		return p.parseObjectEvents(h)
+	case tokenNum:
+		val, err = p.parseNum()
+	case tokenString:
+		val, err = p.parseString()
+	case tokenBool:
+		val, err = p.parseBool()
+	case tokenNull:
+		val, err = p.parseNull()
+	default:
+		return p.unexpectedTokenError("unknown kind of token", valueTokenKinds...)
+	}
+	if err != nil {
+		return err
+	}
+
+	return h.Scalar(val, at)
+}
+
+func (p *parser) parseArrayEvents(h Handler) error {
+	if err := h.StartArray(newPosition(p.currTok.pos)); err != nil {
+		if errors.Is(err, ErrSkip) {
+			return p.skipEvents()
+		}
+		return err
+	}
+
+	if err := p.enter(); err != nil {
+		return err
+	}
+	defer p.leave()
+
+	p.readToken()
+
+	if !p.doHaveToken(tokenRBracket) {
+		for {
+			if err := p.parseEvents(h); err != nil {
+				return err
+			}
+
+			if !p.doHaveToken(tokenComma) {
+				break
+			}
+			p.readToken()
+			if p.allowsTrailingComma() && p.doHaveToken(tokenRBracket) {
+				break
+			}
+		}
+	}
+	if !p.doHaveToken(tokenRBracket) {
+		return p.unexpectedTokenError("invalid array format: array should end with ']'", tokenComma, tokenRBracket)
+	}
+
+	at := newPosition(p.currTok.pos)
+	p.readToken()
+
+	return h.EndArray(at)
+}
+
+func (p *parser) parseObjectEvents(h Handler) error {
+	if err := h.StartObject(newPosition(p.currTok.pos)); err != nil {
+		if errors.Is(err, ErrSkip) {
+			return p.skipEvents()
+		}
+		return err
+	}
+
+	if err := p.enter(); err != nil {
+		return err
+	}
+	defer p.leave()
+
+	p.readToken()
+
+	if !p.doHaveToken(tokenRBrace) {
+		for {
+			if err := p.parsePropEvents(h); err != nil {
+				return fmt.Errorf("failed to parse prop: %w", err)
+			}
+
+			if !p.doHaveToken(tokenComma) {
+				break
+			}
+			p.readToken()
+			if p.allowsTrailingComma() && p.doHaveToken(tokenRBrace) {
+				break
+			}
+		}
+	}
+	if !p.doHaveToken(tokenRBrace) {
+		return p.unexpectedTokenError("invalid object format: object should end with '}'", tokenComma, tokenRBrace)
+	}
+
+	at := newPosition(p.currTok.pos)
+	p.readToken()
+
+	return h.EndObject(at)
+}
+
+func (p *parser) parsePropEvents(h Handler) error {
+	at := newPosition(p.currTok.pos)
+	key, _, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+
+	if !p.doHaveToken(tokenColon) {
+		return p.unexpectedTokenError("invalid prop format: prop should be composed of key and value separated by ':'", tokenColon)
+	}
+	p.readToken()
+
+	if err := h.Key(string(key), at); err != nil {
+		if errors.Is(err, ErrSkip) {
+			return p.skipEvents()
+		}
+		return err
+	}
+
+	if err := p.parseEvents(h); err != nil {
+		return fmt.Errorf("failed to parse value: %w", err)
+	}
+
+	return nil
+}
+
+// skipEvents skips the value which starts with the current token without emitting the events for it.
+// The value is parsed in the same way as the other values so that the invalid one is reported,
+// but none of its scalars is kept.
+func (p *parser) skipEvents() error {
+	return p.parseEvents(nopHandler{})
+}
+
+// nopHandler ignores all the events.
+type nopHandler struct{}
+
+func (nopHandler) StartObject(Position) error { return nil }
+
+func (nopHandler) Key(string, Position) error { return nil }
+
+func (nopHandler) EndObject(Position) error { return nil }
+
+func (nopHandler) StartArray(Position) error { return nil }
+
+func (nopHandler) EndArray(Position) error { return nil }
+
+func (nopHandler) Scalar(Value, Position) error { return nil }
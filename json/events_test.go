@@ -0,0 +1,240 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseEvents(t *testing.T) {
+	src := `{
+  "a": [1, "x"],
+  "b": {"c": null}
+}`
+	expected := []string{
+		"start object at 1:1",
+		`key "a" at 2:3`,
+		"start array at 2:8",
+		"scalar 1 at 2:9",
+		`scalar "x" at 2:12`,
+		"end array at 2:15",
+		`key "b" at 3:3`,
+		"start object at 3:8",
+		`key "c" at 3:9`,
+		"scalar null at 3:14",
+		"end object at 3:18",
+		"end object at 4:1",
+	}
+
+	h := &recordingHandler{}
+	if err := ParseEvents(strings.NewReader(src), h); err != nil {
+		t.Errorf("should have parsed: %s", err)
+		return
+	}
+	if err := assertEvents(h.events, expected); err != nil {
+		t.Errorf("should have emitted the events: %s", err)
+		return
+	}
+}
+
+func TestParseEventsStopsAndSkips(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		h        *recordingHandler
+		expected []string
+	}{
+		"stop": {
+			src: `[1, 2, 3]`,
+			h: &recordingHandler{
+				returns: map[string]error{"scalar 2 at 1:5": ErrStop},
+			},
+			expected: []string{"start array at 1:1", "scalar 1 at 1:2", "scalar 2 at 1:5"},
+		},
+		"skip array": {
+			src: `[[1, [2]], 3]`,
+			h: &recordingHandler{
+				returns: map[string]error{"start array at 1:2": ErrSkip},
+			},
+			expected: []string{"start array at 1:1", "start array at 1:2", "scalar 3 at 1:12", "end array at 1:13"},
+		},
+		"skip prop": {
+			src: `{"a": {"b": [1]}, "c": 2}`,
+			h: &recordingHandler{
+				returns: map[string]error{`key "a" at 1:2`: ErrSkip},
+			},
+			expected: []string{"start object at 1:1", `key "a" at 1:2`, `key "c" at 1:19`, "scalar 2 at 1:24", "end object at 1:25"},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			if err := ParseEvents(strings.NewReader(test.src), test.h); err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+			if err := assertEvents(test.h.events, test.expected); err != nil {
+				t.Errorf("should have emitted the events: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestParseEventsFails(t *testing.T) {
+	handlerErr := errors.New("handler error")
+	tests := map[string]struct {
+		opts ParserOptions
+		src  string
+		h    *recordingHandler
+	}{
+		"invalid value": {
+			src: `[1, tru]`,
+		},
+		"missing closing": {
+			src: `{"a": 1`,
+		},
+		"unbalanced skipped value": {
+			src: `{"a": [1}}`,
+			h: &recordingHandler{
+				returns: map[string]error{`key "a" at 1:2`: ErrSkip},
+			},
+		},
+		"invalid skipped scalar": {
+			src: `{"a": 01}`,
+			h: &recordingHandler{
+				returns: map[string]error{`key "a" at 1:2`: ErrSkip},
+			},
+		},
+		"missing element in skipped value": {
+			src: `{"s": [1,,2]}`,
+			h: &recordingHandler{
+				returns: map[string]error{`key "s" at 1:2`: ErrSkip},
+			},
+		},
+		"invalid prop in skipped value": {
+			src: `{"s": {"x" 1 : :}}`,
+			h: &recordingHandler{
+				returns: map[string]error{`key "s" at 1:2`: ErrSkip},
+			},
+		},
+		"non string key in skipped value": {
+			src: `{"s": {1: 2}}`,
+			h: &recordingHandler{
+				returns: map[string]error{`key "s" at 1:2`: ErrSkip},
+			},
+		},
+		"invalid number in skipped array": {
+			src: `[[01]]`,
+			h: &recordingHandler{
+				returns: map[string]error{"start array at 1:2": ErrSkip},
+			},
+		},
+		"invalid escape in skipped object": {
+			src: `[{"a": "\q"}]`,
+			h: &recordingHandler{
+				returns: map[string]error{"start object at 1:2": ErrSkip},
+			},
+		},
+		"trailing data": {
+			opts: ParserOptions{DisallowTrailingData: true},
+			src:  `[] {}`,
+		},
+		"too deep": {
+			opts: ParserOptions{MaxDepth: 1},
+			src:  `[[]]`,
+		},
+		"too deep skipped value": {
+			opts: ParserOptions{MaxDepth: 10},
+			src:  `{"a": ` + strings.Repeat("[", 100) + strings.Repeat("]", 100) + `}`,
+			h: &recordingHandler{
+				returns: map[string]error{`key "a" at 1:2`: ErrSkip},
+			},
+		},
+		"handler error": {
+			src: `[1]`,
+			h: &recordingHandler{
+				returns: map[string]error{"scalar 1 at 1:2": handlerErr},
+			},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			h := test.h
+			if h == nil {
+				h = &recordingHandler{}
+			}
+			if err := test.opts.ParseEvents(strings.NewReader(test.src), h); err == nil {
+				t.Errorf("should have failed to parse: %s", test.src)
+				return
+			}
+		})
+	}
+}
+
+func BenchmarkParseEvents(b *testing.B) {
+	src := `[` + strings.Repeat(`{"id": 12345, "name": "aiueo", "tags": [true, false, null], "nested": {"a": [1, 2, 3]}},`, 5000) + `{"id": 0}]`
+
+	b.Run("events", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := ParseEvents(strings.NewReader(src), nopHandler{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("tree", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := ParseString(src); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// recordingHandler records the events as strings, and returns the error for the event if any.
+type recordingHandler struct {
+	events  []string
+	returns map[string]error
+}
+
+func (h *recordingHandler) StartObject(at Position) error {
+	return h.record("start object", at)
+}
+
+func (h *recordingHandler) Key(key string, at Position) error {
+	return h.record(fmt.Sprintf("key %q", key), at)
+}
+
+func (h *recordingHandler) EndObject(at Position) error {
+	return h.record("end object", at)
+}
+
+func (h *recordingHandler) StartArray(at Position) error {
+	return h.record("start array", at)
+}
+
+func (h *recordingHandler) EndArray(at Position) error {
+	return h.record("end array", at)
+}
+
+func (h *recordingHandler) Scalar(val Value, at Position) error {
+	return h.record("scalar "+compactString(val), at)
+}
+
+func (h *recordingHandler) record(event string, at Position) error {
+	event = fmt.Sprintf("%s at %d:%d", event, at.Line, at.Column)
+	h.events = append(h.events, event)
+
+	return h.returns[event]
+}
+
+func assertEvents(actual, expected []string) error {
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		return reportUnexpected("events", actual, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// NewExtractor returns the extractor for the given paths, which are JSONPath queries
+// consisting only of names, non-negative indexes and wildcards such as $.items[*].id.
+func NewExtractor(paths ...string) (*Extractor, error) {
+	e := &Extractor{}
+	for _, path := range paths {
+		steps, err := compileExtractPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile %s: %w", path, err)
+		}
+		e.paths = append(e.paths, steps)
+	}
+
+	return e, nil
+}
+
+// Extractor extracts the values at its paths while it scans a JSON document.
+// It builds only the values at the paths and skips the subtrees which none of the paths can reach,
+// so extracting a few values from a large document costs little more than reading it.
+type Extractor struct {
+	paths [][]extractStep
+	opts  ParserOptions
+}
+
+// extractStep is a step of the path, which is either a name, an index or a wildcard.
+type extractStep struct {
+	name       string
+	index      int
+	isIndex    bool
+	isWildcard bool
+}
+
+func (s extractStep) matches(elem pathElem) bool {
+	switch {
+	case s.isWildcard:
+		return true
+	case s.isIndex:
+		return elem.isIndex && elem.index == s.index
+	default:
+		return !elem.isIndex && elem.key == s.name
+	}
+}
+
+func compileExtractPath(src string) ([]extractStep, error) {
+	q, err := ParseQuery(src)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]extractStep, len(q.segments))
+	for i, seg := range q.segments {
+		if seg.descendant || len(seg.selectors) != 1 {
+			return nil, fmt.Errorf("path should consist only of names, non-negative indexes and wildcards")
+		}
+
+		switch sel := seg.selectors[0].(type) {
+		case nameSelector:
+			steps[i] = extractStep{name: sel.name}
+		case indexSelector:
+			if sel.index < 0 {
+				return nil, fmt.Errorf("path should consist only of names, non-negative indexes and wildcards")
+			}
+			steps[i] = extractStep{index: sel.index, isIndex: true}
+		case wildcardSelector:
+			steps[i] = extractStep{isWildcard: true}
+		default:
+			return nil, fmt.Errorf("path should consist only of names, non-negative indexes and wildcards")
+		}
+	}
+
+	return steps, nil
+}
+
+// SetParserOptions sets the options with which the extractor parses documents.
+func (e *Extractor) SetParserOptions(opts ParserOptions) {
+	e.opts = opts
+}
+
+// Extract scans the source calling fn for each value at any of the paths in the document order,
+// where the value which contains other matched values precedes them.
+// The function can return ErrStop to stop scanning without any error.
+// The skipped subtrees are validated in the same way as the extracted values.
+func (e *Extractor) Extract(r io.Reader, fn func(Match) error) error {
+	if e.opts.MaxDocumentSize > 0 {
+		r = &sizeLimitedReader{
+			r:         r,
+			max:       e.opts.MaxDocumentSize,
+			remaining: e.opts.MaxDocumentSize,
+		}
+	}
+
+	p := e.opts.newParser(r)
+	err := e.extractDocument(p, fn)
+	if p.lex.err != nil {
+		return fmt.Errorf("failed to read: %w", p.lex.err)
+	}
+	if errors.Is(err, ErrStop) {
+		return nil
+	}
+
+	return err
+}
+
+func (e *Extractor) extractDocument(p *parser, fn func(Match) error) error {
+	active := make([]int, len(e.paths))
+	for i := range e.paths {
+		active[i] = i
+	}
+	// The path has the capacity for the usual depth so that the paths of the children do not allocate.
+	if err := e.extract(p, make(valuePath, 0, 16), active, fn); err != nil {
+		return err
+	}
+
+	if err := p.checkTokenCount(); err != nil {
+		return err
+	}
+	if p.opts.DisallowTrailingData && !p.doHaveToken(tokenEOF) {
+		return p.unexpectedTokenError("invalid document: document should have only one value", tokenEOF)
+	}
+
+	return nil
+}
+
+// extract scans the value at the path which starts with the current token,
+// where active is the indexes of the paths which the path matches so far.
+func (e *Extractor) extract(p *parser, path valuePath, active []int, fn func(Match) error) error {
+	if len(active) == 0 {
+		return p.skipEvents()
+	}
+
+	for _, i := range active {
+		if len(e.paths[i]) == len(path) {
+			val, err := p.parse()
+			if err != nil {
+				return err
+			}
+			return e.emit(val, path, active, fn)
+		}
+	}
+
+	switch p.currTok.kind {
+	case tokenLBracket:
+		return e.extractArray(p, path, active, fn)
+	case tokenLBrace:
+		return e.extractObject(p, path, active, fn)
+	default:
+		return p.skipEvents()
+	}
+}
+
+func (e *Extractor) extractArray(p *parser, path valuePath, active []int, fn func(Match) error) error {
+	if err := p.enter(); err != nil {
+		return err
+	}
+	defer p.leave()
+
+	p.readToken()
+
+	if !p.doHaveToken(tokenRBracket) {
+		for i := 0; ; i++ {
+			elemPath := append(path, indexElem(i))
+			if err := e.extract(p, elemPath, e.advance(elemPath, active), fn); err != nil {
+				return err
+			}
+
+			if !p.doHaveToken(tokenComma) {
+				break
+			}
+			p.readToken()
+			if p.allowsTrailingComma() && p.doHaveToken(tokenRBracket) {
+				break
+			}
+		}
+	}
+	if !p.doHaveToken(tokenRBracket) {
+		return p.unexpectedTokenError("invalid array format: array should end with ']'", tokenComma, tokenRBracket)
+	}
+	p.readToken()
+
+	return nil
+}
+
+func (e *Extractor) extractObject(p *parser, path valuePath, active []int, fn func(Match) error) error {
+	if err := p.enter(); err != nil {
+		return err
+	}
+	defer p.leave()
+
+	p.readToken()
+
+	if !p.doHaveToken(tokenRBrace) {
+		for {
+			if err := e.extractProp(p, path, active, fn); err != nil {
+				return fmt.Errorf("failed to parse prop: %w", err)
+			}
+
+			if !p.doHaveToken(tokenComma) {
+				break
+			}
+			p.readToken()
+			if p.allowsTrailingComma() && p.doHaveToken(tokenRBrace) {
+				break
+			}
+		}
+	}
+	if !p.doHaveToken(tokenRBrace) {
+		return p.unexpectedTokenError("invalid object format: object should end with '}'", tokenComma, tokenRBrace)
+	}
+	p.readToken()
+
+	return nil
+}
+
+func (e *Extractor) extractProp(p *parser, path valuePath, active []int, fn func(Match) error) error {
+	key, _, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+
+	if !p.doHaveToken(tokenColon) {
+		return p.unexpectedTokenError("invalid prop format: prop should be composed of key and value separated by ':'", tokenColon)
+	}
+	p.readToken()
+
+	propPath := append(path, keyElem(string(key)))
+	if err := e.extract(p, propPath, e.advance(propPath, active), fn); err != nil {
+		return fmt.Errorf("failed to parse value: %w", err)
+	}
+
+	return nil
+}
+
+// emit calls fn for the value which has been built and the values in it at the paths in the document order.
+func (e *Extractor) emit(val Value, path valuePath, active []int, fn func(Match) error) error {
+	emitted, goesDeeper := false, false
+	for _, i := range active {
+		if len(e.paths[i]) > len(path) {
+			goesDeeper = true
+			continue
+		}
+		if !emitted {
+			if err := fn(Match{Value: val, path: append(valuePath(nil), path...)}); err != nil {
+				return err
+			}
+			emitted = true
+		}
+	}
+	if !goesDeeper {
+		return nil
+	}
+
+	switch val := val.(type) {
+	case Array:
+		for i, elem := range val {
+			elemPath := append(path, indexElem(i))
+			if err := e.emit(elem, elemPath, e.advance(elemPath, active), fn); err != nil {
+				return err
+			}
+		}
+	case Object:
+		for _, prop := range val.Props() {
+			propPath := append(path, keyElem(string(prop.key)))
+			if err := e.emit(prop.val, propPath, e.advance(propPath, active), fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// advance returns the indexes of the active paths which still match the path whose last element has been appended.
+func (e *Extractor) advance(path valuePath, active []int) []int {
+	var advanced []int
+	depth := len(path) - 1
+	for _, i := range active {
+		if steps := e.paths[i]; depth < len(steps) && steps[depth].matches(path[depth]) {
+			advanced = append(advanced, i)
+		}
+	}
+
+	return advanced
+}
@@ -0,0 +1,218 @@
+package json
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExtractorExtract(t *testing.T) {
+	src := `{
+  "meta": {"skipped": [1, {"id": 0}]},
+  "items": [
+    {"id": 1, "tags": ["a"]},
+    {"name": "no id"},
+    {"id": {"nested": true}, "tags": []}
+  ],
+  "total": 3
+}`
+	tests := map[string]struct {
+		paths    []string
+		expected []string
+	}{
+		"wildcard": {
+			paths:    []string{"$.items[*].id"},
+			expected: []string{"$['items'][0]['id'] 1", `$['items'][2]['id'] {"nested":true}`},
+		},
+		"index": {
+			paths:    []string{"$.items[2].tags", "$['total']"},
+			expected: []string{"$['items'][2]['tags'] []", "$['total'] 3"},
+		},
+		"nested matches": {
+			paths: []string{"$.items[0]", "$.items[0].tags[0]"},
+			expected: []string{
+				`$['items'][0] {"id":1,"tags":["a"]}`,
+				`$['items'][0]['tags'][0] "a"`,
+			},
+		},
+		"root": {
+			paths:    []string{"$"},
+			expected: []string{`$ {"meta":{"skipped":[1,{"id":0}]},"items":[{"id":1,"tags":["a"]},{"name":"no id"},{"id":{"nested":true},"tags":[]}],"total":3}`},
+		},
+		"no match": {
+			paths: []string{"$.items[5]", "$.total.a"},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			e, err := NewExtractor(test.paths...)
+			if err != nil {
+				t.Errorf("should have compiled the paths: %s", err)
+				return
+			}
+
+			var actual []string
+			if err := e.Extract(strings.NewReader(src), func(m Match) error {
+				actual = append(actual, m.Path()+" "+compactString(m.Value))
+				return nil
+			}); err != nil {
+				t.Errorf("should have extracted: %s", err)
+				return
+			}
+			if strings.Join(actual, "\n") != strings.Join(test.expected, "\n") {
+				t.Errorf("should have extracted the values: %s", reportUnexpected("matches", actual, test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestExtractorExtractStops(t *testing.T) {
+	e, err := NewExtractor("$[*]")
+	if err != nil {
+		t.Errorf("should have compiled the paths: %s", err)
+		return
+	}
+
+	var actual []string
+	// The broken rest of the source is never read.
+	err = e.Extract(strings.NewReader(`[1, 2, 3, broken`), func(m Match) error {
+		actual = append(actual, compactString(m.Value))
+		if len(actual) == 2 {
+			return ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("should have stopped without any error: %s", err)
+		return
+	}
+	if expected := []string{"1", "2"}; strings.Join(actual, " ") != strings.Join(expected, " ") {
+		t.Errorf("should have extracted the values until it stopped: %s", reportUnexpected("values", actual, expected))
+		return
+	}
+}
+
+func TestExtractorExtractFails(t *testing.T) {
+	fnErr := errors.New("callback error")
+	tests := map[string]struct {
+		opts ParserOptions
+		src  string
+		fn   func(Match) error
+	}{
+		"too deep skipped value": {
+			opts: ParserOptions{MaxDepth: 5},
+			src:  `{"a": ` + strings.Repeat("[", 100) + strings.Repeat("]", 100) + `, "b": 1}`,
+		},
+		"invalid skipped scalar": {
+			src: `{"a": 01, "b": 2}`,
+		},
+		"invalid skipped value": {
+			src: `{"a": [1}, "b": 2}`,
+		},
+		"missing element in skipped value": {
+			src: `{"a": [1,,2], "b": 1}`,
+		},
+		"non string key in skipped value": {
+			src: `{"a": {1: 2}, "b": 1}`,
+		},
+		"invalid number in skipped value": {
+			src: `{"a": [01], "b": 1}`,
+		},
+		"invalid escape in skipped value": {
+			src: `{"a": ["\q"], "b": 1}`,
+		},
+		"invalid extracted value": {
+			src: `{"b": [tru]}`,
+		},
+		"missing closing": {
+			src: `{"b": 1`,
+		},
+		"callback error": {
+			src: `{"b": 1}`,
+			fn: func(Match) error {
+				return fnErr
+			},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			e, err := NewExtractor("$.b")
+			if err != nil {
+				t.Errorf("should have compiled the paths: %s", err)
+				return
+			}
+			e.SetParserOptions(test.opts)
+			fn := test.fn
+			if fn == nil {
+				fn = func(Match) error { return nil }
+			}
+
+			if err := e.Extract(strings.NewReader(test.src), fn); err == nil {
+				t.Errorf("should have failed to extract: %s", test.src)
+				return
+			}
+		})
+	}
+}
+
+func TestNewExtractorFails(t *testing.T) {
+	tests := map[string]string{
+		"invalid query":      `$.`,
+		"descendant segment": `$..id`,
+		"negative index":     `$[-1]`,
+		"slice":              `$[0:2]`,
+		"filter":             `$[?@.id]`,
+		"multiple selectors": `$['a', 'b']`,
+	}
+
+	for n, path := range tests {
+		t.Run(n, func(t *testing.T) {
+			if _, err := NewExtractor(path); err == nil {
+				t.Errorf("should have failed to compile: %s", path)
+				return
+			}
+		})
+	}
+}
+
+func BenchmarkExtractorExtract(b *testing.B) {
+	src := `{"items": [` + strings.Repeat(`{"id": 12345, "name": "aiueo", "tags": [true, false, null], "nested": {"a": [1, 2, 3]}},`, 1000) + `{"id": 0}]}`
+
+	b.Run("extract", func(b *testing.B) {
+		e, err := NewExtractor("$.items[*].id")
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := e.Extract(strings.NewReader(src), func(Match) error { return nil }); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("lex", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			lex := newLexer(strings.NewReader(src))
+			for lex.readToken().kind != tokenEOF {
+			}
+		}
+	})
+	b.Run("select", func(b *testing.B) {
+		q, err := ParseQuery("$.items[*].id")
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			val, err := ParseString(src)
+			if err != nil {
+				b.Fatal(err)
+			}
+			q.Select(val)
+		}
+	})
+}
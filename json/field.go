@@ -0,0 +1,233 @@
+package json
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// field is a struct field which is mapped onto a prop of object.
+type field struct {
+	name string
+	// index is the index sequence to the field from the struct
+	// which may go through embedded structs.
+	index     []int
+	typ       reflect.Type
+	tagged    bool
+	omitEmpty bool
+	asString  bool
+}
+
+type structFields struct {
+	list   []field
+	byName map[string]int
+	// byFoldedName is to match names case-insensitively
+	// in the same way as encoding/json.
+	byFoldedName map[string]int
+}
+
+func (fs structFields) lookup(name string) (field, bool) {
+	if i, ok := fs.byName[name]; ok {
+		return fs.list[i], true
+	}
+	if i, ok := fs.byFoldedName[strings.ToLower(name)]; ok {
+		return fs.list[i], true
+	}
+
+	return field{}, false
+}
+
+var fieldCache sync.Map
+
+func cachedStructFields(t reflect.Type) structFields {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.(structFields)
+	}
+
+	fs := newStructFields(t)
+	cached, _ := fieldCache.LoadOrStore(t, fs)
+
+	return cached.(structFields)
+}
+
+func newStructFields(t reflect.Type) structFields {
+	list := dominantFields(collectFields(t))
+
+	fs := structFields{
+		list:         list,
+		byName:       make(map[string]int, len(list)),
+		byFoldedName: make(map[string]int, len(list)),
+	}
+	for i, f := range list {
+		fs.byName[f.name] = i
+		folded := strings.ToLower(f.name)
+		if _, ok := fs.byFoldedName[folded]; !ok {
+			fs.byFoldedName[folded] = i
+		}
+	}
+
+	return fs
+}
+
+// collectFields collects fields of the given struct type and the embedded structs in it
+// from the shallowest to the deepest.
+func collectFields(t reflect.Type) []field {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var fields []field
+	visited := make(map[reflect.Type]bool)
+	next := []embedded{{typ: t}}
+	for len(next) != 0 {
+		curr := next
+		next = nil
+
+		for _, e := range curr {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				f := e.typ.Field(i)
+
+				index := make([]int, len(e.index), len(e.index)+1)
+				copy(index, e.index)
+				index = append(index, i)
+
+				tag := f.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := parseTag(tag)
+
+				typ := f.Type
+				if typ.Kind() == reflect.Ptr {
+					typ = typ.Elem()
+				}
+				if f.Anonymous && name == "" && typ.Kind() == reflect.Struct {
+					next = append(next, embedded{typ: typ, index: index})
+					continue
+				}
+				if f.PkgPath != "" {
+					continue
+				}
+
+				tagged := name != ""
+				if !tagged {
+					name = f.Name
+				}
+				fields = append(fields, field{
+					name:      name,
+					index:     index,
+					typ:       f.Type,
+					tagged:    tagged,
+					omitEmpty: opts.has("omitempty"),
+					asString:  opts.has("string") && canBeAsString(f.Type),
+				})
+			}
+		}
+	}
+
+	return fields
+}
+
+// dominantFields drops fields which are hidden by other fields with the same name
+// following the rules of Go for embedded fields and the ones of encoding/json for tags.
+func dominantFields(fields []field) []field {
+	byName := make(map[string][]field)
+	var names []string
+	for _, f := range fields {
+		if _, ok := byName[f.name]; !ok {
+			names = append(names, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	var dominants []field
+	for _, name := range names {
+		if f, ok := dominantField(byName[name]); ok {
+			dominants = append(dominants, f)
+		}
+	}
+
+	sort.SliceStable(dominants, func(i, j int) bool {
+		return lessIndex(dominants[i].index, dominants[j].index)
+	})
+
+	return dominants
+}
+
+func dominantField(fields []field) (field, bool) {
+	// fields are sorted by depth as they are collected from the shallowest.
+	depth := len(fields[0].index)
+	var candidates []field
+	for _, f := range fields {
+		if len(f.index) != depth {
+			break
+		}
+		candidates = append(candidates, f)
+	}
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+
+	var tagged []field
+	for _, f := range candidates {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+
+	return field{}, false
+}
+
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return len(a) < len(b)
+}
+
+func canBeAsString(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+type tagOptions []string
+
+func parseTag(tag string) (string, tagOptions) {
+	splitted := strings.Split(tag, ",")
+	return splitted[0], tagOptions(splitted[1:])
+}
+
+func (opts tagOptions) has(name string) bool {
+	for _, opt := range opts {
+		if opt == name {
+			return true
+		}
+	}
+
+	return false
+}
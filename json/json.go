@@ -0,0 +1,407 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func Parse(src []byte) (Value, error) {
+	return parse(bytes.NewReader(src))
+}
+
+func ParseString(src string) (Value, error) {
+	return parse(strings.NewReader(src))
+}
+
+func parse(r io.Reader) (Value, error) {
+	lex := newLexer(r)
+	p := newParser(lex)
+
+	val, err := p.parse()
+	if p.lex.err != nil {
+		return nil, fmt.Errorf("failed to read: %w", p.lex.err)
+	}
+
+	return val, err
+}
+
+// ParserOptions is the options to parse JSON.
+// The zero value parses in the same way as Parse, which has no limits.
+type ParserOptions struct {
+	// Dialect is the dialect of JSON in which the source is written.
+	Dialect Dialect
+	// DisallowTrailingData makes it an error that the source has anything but whitespaces after the value.
+	DisallowTrailingData bool
+	// DuplicateKeys is the policy for the props of an object which have the same key.
+	DuplicateKeys DuplicateKeyPolicy
+
+	// The limits below are unlimited if they are zero.
+
+	// MaxDepth is the max number of arrays and objects which nest, where [[1]] has the depth of 2.
+	MaxDepth int
+	// MaxStringLength is the max number of characters of strings including keys after they are unescaped.
+	MaxStringLength int
+	// MaxDocumentSize is the max number of bytes of the source.
+	// The source is not read beyond the limit.
+	MaxDocumentSize int
+	// MaxTokens is the max number of tokens such as '[', ',' and strings in the source.
+	MaxTokens int
+}
+
+// Dialect is a dialect of JSON.
+type Dialect int
+
+const (
+	// DialectJSON is JSON defined in RFC 8259.
+	DialectJSON Dialect = iota
+	// DialectJSONC is JSON with comments, which allows // and /* */ comments
+	// and trailing commas in arrays and objects.
+	DialectJSONC
+	// DialectJSON5 is JSON5, which allows what JSONC allows, unquoted identifier keys, single quoted strings,
+	// hexadecimal numbers, Infinity, NaN, leading and trailing decimal points and explicit '+' signs.
+	//
+	// The numbers are converted into the equivalent literals of JSON such as 31 for 0x1F,
+	// except that Infinity, -Infinity and NaN are kept as they are.
+	// They can be converted with Num.Float64, but cannot be encoded.
+	DialectJSON5
+)
+
+// DuplicateKeyPolicy tells how to handle the props of an object which have the same key.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysKeep keeps all the props as they are,
+	// where the last one is effective when the object is unmarshaled.
+	DuplicateKeysKeep DuplicateKeyPolicy = iota
+	// DuplicateKeysError makes it a syntax error that an object has props with the same key.
+	DuplicateKeysError
+	// DuplicateKeysFirstWins keeps only the first one of the props with the same key.
+	DuplicateKeysFirstWins
+	// DuplicateKeysLastWins keeps only the value of the last one of the props with the same key
+	// at the position of the first one.
+	DuplicateKeysLastWins
+)
+
+func (o ParserOptions) Parse(src []byte) (Value, error) {
+	return o.parse(bytes.NewReader(src))
+}
+
+func (o ParserOptions) ParseString(src string) (Value, error) {
+	return o.parse(strings.NewReader(src))
+}
+
+func (o ParserOptions) parse(r io.Reader) (Value, error) {
+	if o.MaxDocumentSize > 0 {
+		r = &sizeLimitedReader{
+			r:         r,
+			max:       o.MaxDocumentSize,
+			remaining: o.MaxDocumentSize,
+		}
+	}
+
+	p := o.newParser(r)
+	val, err := p.parseDocument()
+	if p.lex.err != nil {
+		return nil, fmt.Errorf("failed to read: %w", p.lex.err)
+	}
+
+	return val, err
+}
+
+// ParseRecovering parses the source with the default options recovering from syntax errors.
+func ParseRecovering(src []byte) (Value, []*SyntaxError) {
+	return ParserOptions{}.ParseRecovering(src)
+}
+
+// ParseRecovering parses the source recovering from syntax errors so that it reports all the problems at once
+// rather than only the first one.
+// It resynchronizes at ',', ']' and '}' skipping what cannot be parsed,
+// and inserts the missing ',', ':', ']' and '}' where it can.
+// Anything after the value is reported as well as the errors in it regardless of DisallowTrailingData.
+//
+// It returns the best-effort value, from which the invalid values are dropped, and the diagnostics in the order they are found.
+// The value is nil if there is no valid value.
+// The limits such as MaxDepth still stop parsing, where the value is nil and the last diagnostic is the one for the limit.
+func (o ParserOptions) ParseRecovering(src []byte) (Value, []*SyntaxError) {
+	if o.MaxDocumentSize > 0 && len(src) > o.MaxDocumentSize {
+		return nil, []*SyntaxError{
+			newSyntaxError(pos{}, "too large document: document should be at most %d bytes", o.MaxDocumentSize),
+		}
+	}
+
+	p := o.newParser(bytes.NewReader(src))
+	p.recovering = true
+
+	val, err := p.parseDocument()
+	if err != nil {
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			syntaxErr = newSyntaxError(p.currTok.pos, "%s", err)
+		}
+		return nil, append(p.diagnostics, syntaxErr)
+	}
+
+	return val, p.diagnostics
+}
+
+func (o ParserOptions) newParser(r io.Reader) *parser {
+	lex := newLexer(r)
+	lex.dialect = o.Dialect
+	lex.maxStringLength = o.MaxStringLength
+	p := newParser(lex)
+	p.opts = o
+
+	return &p
+}
+
+// sizeLimitedReader fails to read once its source turns out to be larger than the max.
+type sizeLimitedReader struct {
+	r              io.Reader
+	max, remaining int
+}
+
+func (r *sizeLimitedReader) Read(b []byte) (int, error) {
+	// Read 1 more byte than the remaining to know whether the source is larger than the max.
+	if len(b) > r.remaining+1 {
+		b = b[:r.remaining+1]
+	}
+
+	n, err := r.r.Read(b)
+	if n > r.remaining {
+		n, r.remaining = r.remaining, 0
+		return n, fmt.Errorf("document should be at most %d bytes", r.max)
+	}
+	r.remaining -= n
+
+	return n, err
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r: r,
+	}
+}
+
+// Decoder decodes JSON values one after another from its reader.
+// It also reads them token by token with Token, between which Decode can decode the value at any nested position,
+// so that only the needed values are built from a huge document.
+type Decoder struct {
+	r      io.Reader
+	parser *parser
+	// states is the stack of the states in the arrays and the objects which enclose the current token.
+	states []decoderState
+}
+
+// decoderState is the state of the decoder in an array or an object, which tells what comes next.
+type decoderState int
+
+const (
+	// decoderArrayStart is after '[', where a value or ']' comes next.
+	decoderArrayStart decoderState = iota
+	// decoderArrayValue is after a value in an array, where ',' or ']' comes next.
+	decoderArrayValue
+	// decoderArrayComma is after ',' in an array, where a value comes next.
+	decoderArrayComma
+	// decoderObjectStart is after '{', where a key or '}' comes next.
+	decoderObjectStart
+	// decoderObjectKey is after a key, where ':' comes next.
+	decoderObjectKey
+	// decoderObjectColon is after ':', where a value comes next.
+	decoderObjectColon
+	// decoderObjectValue is after a value in an object, where ',' or '}' comes next.
+	decoderObjectValue
+	// decoderObjectComma is after ',' in an object, where a key comes next.
+	decoderObjectComma
+)
+
+// Token is a token of JSON values which Decoder reads.
+// It is Delim for the brackets of arrays and objects, String for the keys of objects,
+// and Null, Bool, Num or String for the other values.
+// The commas and the colons are not reported.
+type Token interface{}
+
+// Delim is one of '[', ']', '{' and '}'.
+type Delim rune
+
+func (d Delim) String() string {
+	return string(d)
+}
+
+// Decode decodes the next JSON value into v.
+// It returns io.EOF when there is no more value to decode.
+func (d *Decoder) Decode(v *Value) error {
+	if d.parser == nil {
+		d.init()
+	}
+
+	if err := d.skipSeparator(); err != nil {
+		return err
+	}
+	if len(d.states) == 0 && d.parser.lex.err == nil && d.parser.doHaveToken(tokenEOF) {
+		return io.EOF
+	}
+	if !d.expectsValue() {
+		return d.unexpectedTokenError()
+	}
+
+	decoded, err := d.parser.parse()
+	if d.parser.lex.err != nil {
+		return fmt.Errorf("failed to read: %w", d.parser.lex.err)
+	}
+	if err != nil {
+		return err
+	}
+	d.endValue()
+
+	*v = decoded
+
+	return nil
+}
+
+// Token returns the next token in the input stream.
+// It returns io.EOF when there is no more token at the top level.
+func (d *Decoder) Token() (Token, error) {
+	if d.parser == nil {
+		d.init()
+	}
+
+	if err := d.skipSeparator(); err != nil {
+		return nil, err
+	}
+	if d.parser.lex.err != nil {
+		return nil, fmt.Errorf("failed to read: %w", d.parser.lex.err)
+	}
+
+	p := d.parser
+	switch {
+	case len(d.states) == 0 && p.doHaveToken(tokenEOF):
+		return nil, io.EOF
+	case p.doHaveToken(tokenLBracket) && d.expectsValue():
+		p.readToken()
+		d.states = append(d.states, decoderArrayStart)
+		return Delim('['), nil
+	case p.doHaveToken(tokenLBrace) && d.expectsValue():
+		p.readToken()
+		d.states = append(d.states, decoderObjectStart)
+		return Delim('{'), nil
+	case p.doHaveToken(tokenRBracket) && d.doHaveState(decoderArrayStart, decoderArrayValue):
+		p.readToken()
+		d.states = d.states[:len(d.states)-1]
+		d.endValue()
+		return Delim(']'), nil
+	case p.doHaveToken(tokenRBrace) && d.doHaveState(decoderObjectStart, decoderObjectValue):
+		p.readToken()
+		d.states = d.states[:len(d.states)-1]
+		d.endValue()
+		return Delim('}'), nil
+	case p.doHaveToken(tokenString) && d.doHaveState(decoderObjectStart, decoderObjectComma):
+		key, err := p.parseString()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key: %w", err)
+		}
+		d.states[len(d.states)-1] = decoderObjectKey
+		return key, nil
+	case p.doHaveValueToken() && d.expectsValue():
+		val, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		d.endValue()
+		return val, nil
+	default:
+		return nil, d.unexpectedTokenError()
+	}
+}
+
+// More reports whether there is another element in the current array or object.
+func (d *Decoder) More() bool {
+	if d.parser == nil {
+		d.init()
+	}
+
+	return d.parser.lex.err == nil && !d.parser.doHaveToken(tokenRBracket) && !d.parser.doHaveToken(tokenRBrace) && !d.parser.doHaveToken(tokenEOF)
+}
+
+// skipSeparator reads the ',' or ':' which separates the values as the decoder does not report them.
+func (d *Decoder) skipSeparator() error {
+	var next decoderState
+	switch {
+	case d.parser.doHaveToken(tokenComma) && d.doHaveState(decoderArrayValue):
+		next = decoderArrayComma
+	case d.parser.doHaveToken(tokenComma) && d.doHaveState(decoderObjectValue):
+		next = decoderObjectComma
+	case d.parser.doHaveToken(tokenColon) && d.doHaveState(decoderObjectKey):
+		next = decoderObjectColon
+	default:
+		if d.doHaveState(decoderObjectKey) {
+			return d.unexpectedTokenError()
+		}
+		return nil
+	}
+
+	d.parser.readToken()
+	d.states[len(d.states)-1] = next
+
+	return nil
+}
+
+// expectsValue reports whether a value comes next.
+func (d *Decoder) expectsValue() bool {
+	return len(d.states) == 0 || d.doHaveState(decoderArrayStart, decoderArrayComma, decoderObjectColon)
+}
+
+// endValue moves the state forward as a value has been read.
+func (d *Decoder) endValue() {
+	switch {
+	case d.doHaveState(decoderArrayStart, decoderArrayComma):
+		d.states[len(d.states)-1] = decoderArrayValue
+	case d.doHaveState(decoderObjectColon):
+		d.states[len(d.states)-1] = decoderObjectValue
+	}
+}
+
+// doHaveState reports whether the decoder is in any of the given states in the innermost array or object.
+func (d *Decoder) doHaveState(states ...decoderState) bool {
+	if len(d.states) == 0 {
+		return false
+	}
+	for _, state := range states {
+		if d.states[len(d.states)-1] == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *Decoder) unexpectedTokenError() error {
+	if len(d.states) == 0 {
+		return d.parser.unexpectedTokenError("unknown kind of token", valueTokenKinds...)
+	}
+
+	switch d.states[len(d.states)-1] {
+	case decoderArrayStart:
+		return d.parser.unexpectedTokenError("invalid array format: array should have values or end with ']'", tokenLBracket, tokenLBrace, tokenNum, tokenString, tokenBool, tokenNull, tokenRBracket)
+	case decoderArrayValue:
+		return d.parser.unexpectedTokenError("invalid array format: array should end with ']'", tokenComma, tokenRBracket)
+	case decoderObjectStart:
+		return d.parser.unexpectedTokenError("invalid object format: object should have props or end with '}'", tokenString, tokenRBrace)
+	case decoderObjectKey:
+		return d.parser.unexpectedTokenError("invalid prop format: prop should be composed of key and value separated by ':'", tokenColon)
+	case decoderObjectValue:
+		return d.parser.unexpectedTokenError("invalid object format: object should end with '}'", tokenComma, tokenRBrace)
+	case decoderObjectComma:
+		return d.parser.unexpectedTokenError("invalid prop format: key should be string", tokenString)
+	default:
+		return d.parser.unexpectedTokenError("unknown kind of token", valueTokenKinds...)
+	}
+}
+
+func (d *Decoder) init() {
+	lex := newLexer(d.r)
+	p := newParser(lex)
+	d.parser = &p
+}
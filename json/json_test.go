@@ -0,0 +1,365 @@
+package json_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tomocy/go-cookbook/json"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected json.Value
+	}{
+		"null": {
+			src:      "null",
+			expected: json.Null{},
+		},
+		"number": {
+			src:      "-1.5",
+			expected: mustNum("-1.5"),
+		},
+		"string": {
+			src:      `"aiueo"`,
+			expected: json.String("aiueo"),
+		},
+		"array": {
+			src: `[1, "two", true, null]`,
+			expected: json.Array{
+				mustNum("1"),
+				json.String("two"),
+				json.Bool(true),
+				json.Null{},
+			},
+		},
+		"object": {
+			src: `{"a": 1, "b": {"c": [false]}}`,
+			expected: json.NewObject(
+				json.NewProp("a", mustNum("1")),
+				json.NewProp("b", json.NewObject(
+					json.NewProp("c", json.Array{
+						json.Bool(false),
+					}),
+				)),
+			),
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			actual, err := json.Parse([]byte(test.src))
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+			if err := assertValue(actual, test.expected); err != nil {
+				t.Errorf("should have parsed: unexpected value: %s", err)
+				return
+			}
+
+			actual, err = json.ParseString(test.src)
+			if err != nil {
+				t.Errorf("should have parsed string: %s", err)
+				return
+			}
+			if err := assertValue(actual, test.expected); err != nil {
+				t.Errorf("should have parsed string: unexpected value: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	src := `{"a": 1} [true] "three"`
+	expected := []json.Value{
+		json.NewObject(
+			json.NewProp("a", mustNum("1")),
+		),
+		json.Array{
+			json.Bool(true),
+		},
+		json.String("three"),
+	}
+
+	dec := json.NewDecoder(strings.NewReader(src))
+	for _, expected := range expected {
+		var actual json.Value
+		if err := dec.Decode(&actual); err != nil {
+			t.Errorf("should have decoded: %s", err)
+			return
+		}
+		if err := assertValue(actual, expected); err != nil {
+			t.Errorf("should have decoded: unexpected value: %s", err)
+			return
+		}
+	}
+
+	var actual json.Value
+	if err := dec.Decode(&actual); err != io.EOF {
+		t.Errorf("should have reported io.EOF: %s", reportUnexpected("error", err, io.EOF))
+		return
+	}
+}
+
+func TestDecoderToken(t *testing.T) {
+	src := `{"a": [1, {"b": null}], "c": true} "d"`
+	expected := []json.Token{
+		json.Delim('{'),
+		json.String("a"),
+		json.Delim('['),
+		mustNum("1"),
+		json.Delim('{'),
+		json.String("b"),
+		json.Null{},
+		json.Delim('}'),
+		json.Delim(']'),
+		json.String("c"),
+		json.Bool(true),
+		json.Delim('}'),
+		json.String("d"),
+	}
+
+	dec := json.NewDecoder(strings.NewReader(src))
+	for _, expected := range expected {
+		actual, err := dec.Token()
+		if err != nil {
+			t.Errorf("should have read the token: %s", err)
+			return
+		}
+		if fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Errorf("should have read the token: %s", reportUnexpected("token", actual, expected))
+			return
+		}
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		t.Errorf("should have reported io.EOF: %s", reportUnexpected("error", err, io.EOF))
+		return
+	}
+}
+
+func TestDecoderDecodeInToken(t *testing.T) {
+	src := `{"skipped": [[1, 2], {"a": []}], "items": [{"id": 1}, {"id": 2}], "after": "x"}`
+	expected := []json.Value{
+		json.NewObject(json.NewProp("id", mustNum("1"))),
+		json.NewObject(json.NewProp("id", mustNum("2"))),
+	}
+
+	dec := json.NewDecoder(strings.NewReader(src))
+	if _, err := dec.Token(); err != nil {
+		t.Errorf("should have read '{': %s", err)
+		return
+	}
+
+	var actual []json.Value
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			t.Errorf("should have read the key: %s", err)
+			return
+		}
+		if key != json.String("items") {
+			if err := skipValue(dec); err != nil {
+				t.Errorf("should have skipped the value: %s", err)
+				return
+			}
+			continue
+		}
+
+		if _, err := dec.Token(); err != nil {
+			t.Errorf("should have read '[': %s", err)
+			return
+		}
+		for dec.More() {
+			var v json.Value
+			if err := dec.Decode(&v); err != nil {
+				t.Errorf("should have decoded the item: %s", err)
+				return
+			}
+			actual = append(actual, v)
+		}
+		if _, err := dec.Token(); err != nil {
+			t.Errorf("should have read ']': %s", err)
+			return
+		}
+	}
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('}') {
+		t.Errorf("should have read '}': %v: %v", tok, err)
+		return
+	}
+
+	if len(actual) != len(expected) {
+		t.Errorf("should have decoded the items: %s", reportUnexpected("len of items", len(actual), len(expected)))
+		return
+	}
+	for i, expected := range expected {
+		if err := assertValue(actual[i], expected); err != nil {
+			t.Errorf("should have decoded the item: %s", err)
+			return
+		}
+	}
+}
+
+func TestDecoderTokenFails(t *testing.T) {
+	tests := map[string]string{
+		"missing colon":       `{"a" 1}`,
+		"missing comma":       `[1 2]`,
+		"non-string key":      `{1: 2}`,
+		"mismatched brackets": `[1}`,
+		"unexpected end":      `[1,`,
+		"trailing comma":      `[1,]`,
+	}
+
+	for n, src := range tests {
+		t.Run(n, func(t *testing.T) {
+			dec := json.NewDecoder(strings.NewReader(src))
+			for {
+				_, err := dec.Token()
+				if err == io.EOF {
+					t.Errorf("should have failed to read tokens: %s", src)
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		})
+	}
+}
+
+// skipValue skips the next value token by token without building it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('['), json.Delim('{'):
+			depth++
+		case json.Delim(']'), json.Delim('}'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func TestKind(t *testing.T) {
+	tests := map[string]struct {
+		val      json.Value
+		expected json.Kind
+	}{
+		"null": {
+			val:      json.Null{},
+			expected: json.KindNull,
+		},
+		"bool": {
+			val:      json.Bool(true),
+			expected: json.KindBool,
+		},
+		"number": {
+			val:      mustNum("1"),
+			expected: json.KindNum,
+		},
+		"string": {
+			val:      json.String("a"),
+			expected: json.KindString,
+		},
+		"array": {
+			val:      json.Array{},
+			expected: json.KindArray,
+		},
+		"object": {
+			val:      json.NewObject(),
+			expected: json.KindObject,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			if actual := test.val.Kind(); actual != test.expected {
+				t.Errorf("should have returned kind: %s", reportUnexpected("kind", actual, test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestNewNum(t *testing.T) {
+	if _, err := json.NewNum("01"); err == nil {
+		t.Errorf("should have failed to create number with leading zeros")
+		return
+	}
+
+	num, err := json.NewNum("1e2")
+	if err != nil {
+		t.Errorf("should have created number: %s", err)
+		return
+	}
+	if num.String() != "1e2" {
+		t.Errorf("should have kept literal: %s", reportUnexpected("literal", num.String(), "1e2"))
+		return
+	}
+}
+
+func mustNum(lit string) json.Num {
+	num, err := json.NewNum(lit)
+	if err != nil {
+		panic(err)
+	}
+
+	return num
+}
+
+func assertValue(actual, expected json.Value) error {
+	if actual.Kind() != expected.Kind() {
+		return reportUnexpected("kind", actual.Kind(), expected.Kind())
+	}
+
+	switch expected := expected.(type) {
+	case json.Array:
+		actual := actual.(json.Array)
+		if len(actual) != len(expected) {
+			return reportUnexpected("len of value", len(actual), len(expected))
+		}
+		for i, expected := range expected {
+			if err := assertValue(actual[i], expected); err != nil {
+				return fmt.Errorf("unexpected value at %d: %s", i, err)
+			}
+		}
+
+		return nil
+	case json.Object:
+		actual := actual.(json.Object).Props()
+		if len(actual) != expected.Len() {
+			return reportUnexpected("len of value", len(actual), expected.Len())
+		}
+		for i, expected := range expected.Props() {
+			if actual[i].Key() != expected.Key() {
+				return fmt.Errorf("unexpected prop at %d: %s", i, reportUnexpected("key", actual[i].Key(), expected.Key()))
+			}
+			if err := assertValue(actual[i].Value(), expected.Value()); err != nil {
+				return fmt.Errorf("unexpected prop at %d: %s", i, err)
+			}
+		}
+
+		return nil
+	default:
+		if actual != expected {
+			return reportUnexpected("value", actual, expected)
+		}
+
+		return nil
+	}
+}
+
+func reportUnexpected(name string, actual, expected interface{}) error {
+	return fmt.Errorf("unexpected %s: got %v, expected %v", name, actual, expected)
+}
@@ -0,0 +1,653 @@
+package json
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Query is JSONPath query defined in RFC 9535 such as $.items[?@.status == "failed"].id.
+type Query struct {
+	src      string
+	segments []querySegment
+}
+
+// ParseQuery parses the given JSONPath query.
+// It returns SyntaxError if the query is not well-formed or not well-typed.
+func ParseQuery(src string) (*Query, error) {
+	p := newQueryParser(src)
+	q, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// String returns the source of the query.
+func (q *Query) String() string {
+	return q.src
+}
+
+// Select returns the values in the given value which the query matches
+// in the order of RFC 9535, which is the document order for this implementation.
+func (q *Query) Select(root Value) []Match {
+	nodes := evalSegments(root, []node{{val: root}}, q.segments)
+
+	matches := make([]Match, len(nodes))
+	for i, n := range nodes {
+		matches[i] = Match{
+			Value: n.val,
+			path:  n.path,
+		}
+	}
+
+	return matches
+}
+
+// Match is a value which a query matches.
+type Match struct {
+	Value Value
+	path  valuePath
+}
+
+// Path returns the normalized path of the value such as $['items'][0]['id'].
+func (m Match) Path() string {
+	return m.path.normalized()
+}
+
+// Pointer returns the pointer to the value.
+func (m Match) Pointer() Pointer {
+	return m.path.pointer()
+}
+
+// node is a value with its location in the document.
+type node struct {
+	val  Value
+	path valuePath
+}
+
+func (n node) child(key string, val Value) node {
+	return node{
+		val:  val,
+		path: n.path.appended(keyElem(key)),
+	}
+}
+
+func (n node) elem(i int, val Value) node {
+	return node{
+		val:  val,
+		path: n.path.appended(indexElem(i)),
+	}
+}
+
+// children returns the members of object or the elements of array.
+func (n node) children() []node {
+	switch val := n.val.(type) {
+	case Object:
+		children := make([]node, 0, val.Len())
+		val.Range(func(key string, val Value) bool {
+			children = append(children, n.child(key, val))
+			return true
+		})
+		return children
+	case Array:
+		children := make([]node, len(val))
+		for i, elem := range val {
+			children[i] = n.elem(i, elem)
+		}
+		return children
+	default:
+		return nil
+	}
+}
+
+// descendants returns the node and its descendants in which each node precedes its children.
+func (n node) descendants(dst []node) []node {
+	dst = append(dst, n)
+	for _, child := range n.children() {
+		dst = child.descendants(dst)
+	}
+
+	return dst
+}
+
+func evalSegments(root Value, nodes []node, segments []querySegment) []node {
+	for _, seg := range segments {
+		var selected []node
+		for _, n := range nodes {
+			targets := []node{n}
+			if seg.descendant {
+				targets = n.descendants(nil)
+			}
+
+			for _, target := range targets {
+				for _, sel := range seg.selectors {
+					selected = sel.selectNodes(root, target, selected)
+				}
+			}
+		}
+
+		nodes = selected
+	}
+
+	return nodes
+}
+
+// querySegment is either a child segment such as [0, 'a'] or a descendant segment such as ..[0, 'a'].
+type querySegment struct {
+	descendant bool
+	selectors  []selector
+}
+
+// selector appends the nodes which it selects from the given node to dst.
+type selector interface {
+	selectNodes(root Value, n node, dst []node) []node
+}
+
+type nameSelector struct {
+	name string
+}
+
+func (s nameSelector) selectNodes(_ Value, n node, dst []node) []node {
+	obj, ok := n.val.(Object)
+	if !ok {
+		return dst
+	}
+
+	val, ok := obj.Get(s.name)
+	if !ok {
+		return dst
+	}
+
+	return append(dst, n.child(s.name, val))
+}
+
+type wildcardSelector struct{}
+
+func (wildcardSelector) selectNodes(_ Value, n node, dst []node) []node {
+	return append(dst, n.children()...)
+}
+
+type indexSelector struct {
+	index int
+}
+
+func (s indexSelector) selectNodes(_ Value, n node, dst []node) []node {
+	arr, ok := n.val.(Array)
+	if !ok {
+		return dst
+	}
+
+	i := normalizeIndex(s.index, len(arr))
+	if i < 0 || len(arr) <= i {
+		return dst
+	}
+
+	return append(dst, n.elem(i, arr[i]))
+}
+
+type sliceSelector struct {
+	start, end       int
+	hasStart, hasEnd bool
+	step             int
+}
+
+func (s sliceSelector) selectNodes(_ Value, n node, dst []node) []node {
+	arr, ok := n.val.(Array)
+	if !ok || s.step == 0 {
+		return dst
+	}
+
+	if s.step > 0 {
+		start, end := 0, len(arr)
+		if s.hasStart {
+			start = clampIndex(normalizeIndex(s.start, len(arr)), 0, len(arr))
+		}
+		if s.hasEnd {
+			end = clampIndex(normalizeIndex(s.end, len(arr)), 0, len(arr))
+		}
+
+		for i := start; i < end; i += s.step {
+			dst = append(dst, n.elem(i, arr[i]))
+		}
+
+		return dst
+	}
+
+	start, end := len(arr)-1, -1
+	if s.hasStart {
+		start = clampIndex(normalizeIndex(s.start, len(arr)), -1, len(arr)-1)
+	}
+	if s.hasEnd {
+		end = clampIndex(normalizeIndex(s.end, len(arr)), -1, len(arr)-1)
+	}
+
+	for i := start; end < i; i += s.step {
+		dst = append(dst, n.elem(i, arr[i]))
+	}
+
+	return dst
+}
+
+// normalizeIndex converts the negative index which counts from the end into the one from the beginning.
+func normalizeIndex(i, len int) int {
+	if i >= 0 {
+		return i
+	}
+
+	return len + i
+}
+
+func clampIndex(i, min, max int) int {
+	if i < min {
+		return min
+	}
+	if i > max {
+		return max
+	}
+
+	return i
+}
+
+type filterSelector struct {
+	expr filterExpr
+}
+
+func (s filterSelector) selectNodes(root Value, n node, dst []node) []node {
+	for _, child := range n.children() {
+		ctx := filterContext{
+			root: root,
+			curr: child,
+		}
+		if logicalOf(s.expr, ctx) {
+			dst = append(dst, child)
+		}
+	}
+
+	return dst
+}
+
+// filterContext holds the nodes which the queries in filter expressions start with.
+type filterContext struct {
+	root Value
+	curr node
+}
+
+// exprType is the type of filter expressions defined in RFC 9535.
+type exprType string
+
+const (
+	valueExprType   exprType = "ValueType"
+	logicalExprType exprType = "LogicalType"
+	nodesExprType   exprType = "NodesType"
+)
+
+// filterExpr is an expression in filter selectors.
+type filterExpr interface {
+	exprType() exprType
+	eval(ctx filterContext) exprValue
+}
+
+// exprValue is the result of a filter expression which is used according to its type.
+type exprValue struct {
+	// val is nil if it is Nothing.
+	val     Value
+	logical bool
+	nodes   []node
+}
+
+func valueOf(e filterExpr, ctx filterContext) Value {
+	result := e.eval(ctx)
+	if e.exprType() != nodesExprType {
+		return result.val
+	}
+	if len(result.nodes) != 1 {
+		return nil
+	}
+
+	return result.nodes[0].val
+}
+
+func logicalOf(e filterExpr, ctx filterContext) bool {
+	result := e.eval(ctx)
+	if e.exprType() == nodesExprType {
+		return len(result.nodes) != 0
+	}
+
+	return result.logical
+}
+
+type literalExpr struct {
+	val Value
+}
+
+func (literalExpr) exprType() exprType { return valueExprType }
+
+func (e literalExpr) eval(filterContext) exprValue {
+	return exprValue{
+		val: e.val,
+	}
+}
+
+type queryExpr struct {
+	// absolute is true if the query starts with $ rather than @.
+	absolute bool
+	segments []querySegment
+}
+
+func (queryExpr) exprType() exprType { return nodesExprType }
+
+func (e queryExpr) eval(ctx filterContext) exprValue {
+	start := ctx.curr
+	if e.absolute {
+		start = node{val: ctx.root}
+	}
+
+	return exprValue{
+		nodes: evalSegments(ctx.root, []node{start}, e.segments),
+	}
+}
+
+// isSingular reports whether the query selects at most one node
+// as it consists only of child segments with a name or index selector.
+func (e queryExpr) isSingular() bool {
+	for _, seg := range e.segments {
+		if seg.descendant || len(seg.selectors) != 1 {
+			return false
+		}
+		switch seg.selectors[0].(type) {
+		case nameSelector, indexSelector:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+type orExpr struct {
+	exprs []filterExpr
+}
+
+func (orExpr) exprType() exprType { return logicalExprType }
+
+func (e orExpr) eval(ctx filterContext) exprValue {
+	for _, expr := range e.exprs {
+		if logicalOf(expr, ctx) {
+			return exprValue{logical: true}
+		}
+	}
+
+	return exprValue{}
+}
+
+type andExpr struct {
+	exprs []filterExpr
+}
+
+func (andExpr) exprType() exprType { return logicalExprType }
+
+func (e andExpr) eval(ctx filterContext) exprValue {
+	for _, expr := range e.exprs {
+		if !logicalOf(expr, ctx) {
+			return exprValue{}
+		}
+	}
+
+	return exprValue{logical: true}
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (notExpr) exprType() exprType { return logicalExprType }
+
+func (e notExpr) eval(ctx filterContext) exprValue {
+	return exprValue{
+		logical: !logicalOf(e.expr, ctx),
+	}
+}
+
+// parenExpr is a parenthesized logical expression.
+type parenExpr struct {
+	expr filterExpr
+}
+
+func (parenExpr) exprType() exprType { return logicalExprType }
+
+func (e parenExpr) eval(ctx filterContext) exprValue {
+	return exprValue{
+		logical: logicalOf(e.expr, ctx),
+	}
+}
+
+type comparisonOp string
+
+const (
+	opEQ comparisonOp = "=="
+	opNE comparisonOp = "!="
+	opLE comparisonOp = "<="
+	opGE comparisonOp = ">="
+	opLT comparisonOp = "<"
+	opGT comparisonOp = ">"
+)
+
+// comparisonOps are ordered so that the longer operators are tried first.
+var comparisonOps = []comparisonOp{opEQ, opNE, opLE, opGE, opLT, opGT}
+
+type comparisonExpr struct {
+	op          comparisonOp
+	left, right filterExpr
+}
+
+func (comparisonExpr) exprType() exprType { return logicalExprType }
+
+func (e comparisonExpr) eval(ctx filterContext) exprValue {
+	left, right := valueOf(e.left, ctx), valueOf(e.right, ctx)
+
+	var result bool
+	switch e.op {
+	case opEQ:
+		result = equalComparables(left, right)
+	case opNE:
+		result = !equalComparables(left, right)
+	case opLT:
+		result = lessComparables(left, right)
+	case opLE:
+		result = lessComparables(left, right) || equalComparables(left, right)
+	case opGT:
+		result = lessComparables(right, left)
+	case opGE:
+		result = lessComparables(right, left) || equalComparables(left, right)
+	}
+
+	return exprValue{
+		logical: result,
+	}
+}
+
+// equalComparables reports whether the given values are equal where nil is Nothing
+// which is equal only to Nothing.
+func equalComparables(a, b Value) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return equalValues(a, b)
+}
+
+// lessComparables reports whether a is less than b, which is true only for numbers and strings.
+func lessComparables(a, b Value) bool {
+	switch a := a.(type) {
+	case Num:
+		b, ok := b.(Num)
+		return ok && a.cmp(b) < 0
+	case String:
+		b, ok := b.(String)
+		return ok && a < b
+	default:
+		return false
+	}
+}
+
+type functionExpr struct {
+	fn   queryFunction
+	args []filterExpr
+}
+
+func (e functionExpr) exprType() exprType { return e.fn.result }
+
+func (e functionExpr) eval(ctx filterContext) exprValue {
+	return e.fn.call(e.args, ctx)
+}
+
+// queryFunction is a function extension defined in RFC 9535.
+type queryFunction struct {
+	name   string
+	params []exprType
+	result exprType
+	call   func(args []filterExpr, ctx filterContext) exprValue
+}
+
+var queryFunctions = map[string]queryFunction{
+	"length": {
+		name:   "length",
+		params: []exprType{valueExprType},
+		result: valueExprType,
+		call: func(args []filterExpr, ctx filterContext) exprValue {
+			var n int
+			switch val := valueOf(args[0], ctx).(type) {
+			case String:
+				n = utf8.RuneCountInString(string(val))
+			case Array:
+				n = len(val)
+			case Object:
+				n = val.Len()
+			default:
+				return exprValue{}
+			}
+
+			return exprValue{
+				val: NewNumFromInt(int64(n)),
+			}
+		},
+	},
+	"count": {
+		name:   "count",
+		params: []exprType{nodesExprType},
+		result: valueExprType,
+		call: func(args []filterExpr, ctx filterContext) exprValue {
+			return exprValue{
+				val: NewNumFromInt(int64(len(args[0].eval(ctx).nodes))),
+			}
+		},
+	},
+	"match": {
+		name:   "match",
+		params: []exprType{valueExprType, valueExprType},
+		result: logicalExprType,
+		call: func(args []filterExpr, ctx filterContext) exprValue {
+			return exprValue{
+				logical: matchRegexp(valueOf(args[0], ctx), args[1], ctx, true),
+			}
+		},
+	},
+	"search": {
+		name:   "search",
+		params: []exprType{valueExprType, valueExprType},
+		result: logicalExprType,
+		call: func(args []filterExpr, ctx filterContext) exprValue {
+			return exprValue{
+				logical: matchRegexp(valueOf(args[0], ctx), args[1], ctx, false),
+			}
+		},
+	},
+	"value": {
+		name:   "value",
+		params: []exprType{nodesExprType},
+		result: valueExprType,
+		call: func(args []filterExpr, ctx filterContext) exprValue {
+			return exprValue{
+				val: valueOf(args[0], ctx),
+			}
+		},
+	},
+}
+
+// matchRegexp reports whether the pattern which the given expression evaluates to matches the whole or a part of the given string.
+// It is false if either of them is not string or the pattern is invalid.
+//
+// The literal pattern has been compiled when the query is parsed,
+// and the others are compiled every time without being cached as they may come from the documents.
+func matchRegexp(s Value, pattern filterExpr, ctx filterContext, whole bool) bool {
+	str, ok := s.(String)
+	if !ok {
+		return false
+	}
+
+	if compiled, ok := pattern.(patternExpr); ok {
+		return compiled.re != nil && compiled.re.MatchString(string(str))
+	}
+
+	ptn, ok := valueOf(pattern, ctx).(String)
+	if !ok {
+		return false
+	}
+	re, err := compileIRegexp(string(ptn), whole)
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(string(str))
+}
+
+// patternExpr is the literal pattern of match() or search() which has been compiled,
+// where re is nil if the literal is not string or the pattern is invalid.
+type patternExpr struct {
+	literalExpr
+	re *regexp.Regexp
+}
+
+func newPatternExpr(lit literalExpr, whole bool) patternExpr {
+	expr := patternExpr{
+		literalExpr: lit,
+	}
+	if ptn, ok := lit.val.(String); ok {
+		expr.re, _ = compileIRegexp(string(ptn), whole)
+	}
+
+	return expr
+}
+
+// compileIRegexp compiles the given I-Regexp defined in RFC 9485
+// whose '.' does not match '\n' nor '\r' unlike the one of Go.
+func compileIRegexp(pattern string, whole bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	if whole {
+		b.WriteString(`\A(?:`)
+	}
+	var inClass, escaped bool
+	for _, c := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '.' && !inClass:
+			b.WriteString(`[^\n\r]`)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	if whole {
+		b.WriteString(`)\z`)
+	}
+
+	return regexp.Compile(b.String())
+}
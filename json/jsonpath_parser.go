@@ -0,0 +1,679 @@
+package json
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// queryParser parses JSONPath queries following the grammar of RFC 9535.
+type queryParser struct {
+	src string
+	// i is the byte index of the character to read next.
+	i int
+}
+
+func newQueryParser(src string) queryParser {
+	return queryParser{
+		src: src,
+	}
+}
+
+// maxQueryIndex is the max absolute value of indexes and steps, which is the one of I-JSON integers.
+const maxQueryIndex = 1<<53 - 1
+
+func (p *queryParser) parse() (*Query, error) {
+	if !p.consume("$") {
+		return nil, p.errorf(p.i, "invalid query format: query should start with '$'")
+	}
+
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isEOF() {
+		return nil, p.errorf(p.i, "invalid query format: unexpected character '%c'", p.currChar())
+	}
+
+	return &Query{
+		src:      p.src,
+		segments: segments,
+	}, nil
+}
+
+func (p *queryParser) parseSegments() ([]querySegment, error) {
+	var segments []querySegment
+	for {
+		start := p.i
+		p.skipBlanks()
+
+		var seg querySegment
+		var err error
+		switch {
+		case p.consume(".."):
+			seg, err = p.parseDescendantSegment()
+		case p.consume("."):
+			seg, err = p.parseShorthandSegment()
+		case p.currChar() == '[':
+			seg.selectors, err = p.parseBracketedSelection()
+		default:
+			// The blanks belong to what follows the segments.
+			p.i = start
+			return segments, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		segments = append(segments, seg)
+	}
+}
+
+func (p *queryParser) parseDescendantSegment() (querySegment, error) {
+	seg := querySegment{
+		descendant: true,
+	}
+
+	if p.currChar() == '[' {
+		selectors, err := p.parseBracketedSelection()
+		if err != nil {
+			return querySegment{}, err
+		}
+		seg.selectors = selectors
+
+		return seg, nil
+	}
+
+	shorthand, err := p.parseShorthandSegment()
+	if err != nil {
+		return querySegment{}, err
+	}
+	seg.selectors = shorthand.selectors
+
+	return seg, nil
+}
+
+// parseShorthandSegment parses the segment such as .name or .* after the dot.
+func (p *queryParser) parseShorthandSegment() (querySegment, error) {
+	if p.consume("*") {
+		return querySegment{
+			selectors: []selector{wildcardSelector{}},
+		}, nil
+	}
+
+	start := p.i
+	for !p.isEOF() {
+		c := p.currChar()
+		if !isNameFirstChar(c) && (p.i == start || !isNum(c)) {
+			break
+		}
+		p.readChar()
+	}
+	if p.i == start {
+		return querySegment{}, p.errorf(start, "invalid segment format: '.' should be followed by name or '*'")
+	}
+
+	return querySegment{
+		selectors: []selector{nameSelector{name: p.src[start:p.i]}},
+	}, nil
+}
+
+func isNameFirstChar(c rune) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c >= 0x80
+}
+
+func (p *queryParser) parseBracketedSelection() ([]selector, error) {
+	p.readChar()
+
+	var selectors []selector
+	for {
+		p.skipBlanks()
+		sel, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+
+		p.skipBlanks()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.unexpectedCharError("invalid selection format", ",", "]")
+		}
+	}
+}
+
+func (p *queryParser) parseSelector() (selector, error) {
+	switch c := p.currChar(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseStringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return nameSelector{name: name}, nil
+	case c == '*':
+		p.readChar()
+		return wildcardSelector{}, nil
+	case c == '?':
+		p.readChar()
+		p.skipBlanks()
+		return p.parseFilterSelector()
+	default:
+		return p.parseIndexOrSlice()
+	}
+}
+
+func (p *queryParser) parseIndexOrSlice() (selector, error) {
+	start, hasStart, err := p.parseIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipBlanks()
+	if !p.consume(":") {
+		if !hasStart {
+			return nil, p.unexpectedCharError("invalid selector format", "name", "*", "?", "index", "slice")
+		}
+		return indexSelector{index: start}, nil
+	}
+
+	sel := sliceSelector{
+		start:    start,
+		hasStart: hasStart,
+		step:     1,
+	}
+
+	p.skipBlanks()
+	sel.end, sel.hasEnd, err = p.parseIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipBlanks()
+	if p.consume(":") {
+		p.skipBlanks()
+		step, hasStep, err := p.parseIndex()
+		if err != nil {
+			return nil, err
+		}
+		if hasStep {
+			sel.step = step
+		}
+	}
+
+	return sel, nil
+}
+
+// parseIndex parses the integer which may be omitted.
+func (p *queryParser) parseIndex() (int, bool, error) {
+	start := p.i
+	p.consume("-")
+	digitsStart := p.i
+	for !p.isEOF() && isNum(p.currChar()) {
+		p.readChar()
+	}
+
+	lit := p.src[start:p.i]
+	switch {
+	case p.i == start:
+		return 0, false, nil
+	case p.i == digitsStart:
+		return 0, false, p.errorf(start, "invalid index format: '-' should be followed by digits")
+	case lit == "-0" || p.i-digitsStart > 1 && p.src[digitsStart] == '0':
+		return 0, false, p.errorf(start, "invalid index format: %s should not have leading zeros", lit)
+	}
+
+	i, err := strconv.Atoi(lit)
+	if err != nil || i < -maxQueryIndex || maxQueryIndex < i {
+		return 0, false, p.errorf(start, "invalid index format: %s is out of range", lit)
+	}
+
+	return i, true, nil
+}
+
+func (p *queryParser) parseFilterSelector() (selector, error) {
+	start := p.i
+	expr, err := p.parseLogicalExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkLogical(expr, start); err != nil {
+		return nil, err
+	}
+
+	return filterSelector{expr: expr}, nil
+}
+
+// parseLogicalExpr parses the logical expression which may be a bare operand such as literal
+// so that the caller checks if it is the expression which the caller expects.
+func (p *queryParser) parseLogicalExpr() (filterExpr, error) {
+	return p.parseBinaryLogicalExpr("||", p.parseAndExpr, func(exprs []filterExpr) filterExpr {
+		return orExpr{exprs: exprs}
+	})
+}
+
+func (p *queryParser) parseAndExpr() (filterExpr, error) {
+	return p.parseBinaryLogicalExpr("&&", p.parseBasicExpr, func(exprs []filterExpr) filterExpr {
+		return andExpr{exprs: exprs}
+	})
+}
+
+func (p *queryParser) parseBinaryLogicalExpr(op string, parseOperand func() (filterExpr, error), compose func([]filterExpr) filterExpr) (filterExpr, error) {
+	start := p.i
+	first, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []filterExpr{first}
+	for {
+		end := p.i
+		p.skipBlanks()
+		if !p.consume(op) {
+			p.i = end
+			break
+		}
+		p.skipBlanks()
+
+		operandStart := p.i
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkLogical(operand, operandStart); err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, operand)
+	}
+
+	if len(exprs) == 1 {
+		return first, nil
+	}
+	if err := p.checkLogical(first, start); err != nil {
+		return nil, err
+	}
+
+	return compose(exprs), nil
+}
+
+func (p *queryParser) parseBasicExpr() (filterExpr, error) {
+	if p.consume("!") {
+		p.skipBlanks()
+
+		start := p.i
+		var expr filterExpr
+		var err error
+		if p.currChar() == '(' {
+			expr, err = p.parseParenExpr()
+		} else {
+			expr, err = p.parseOperand()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkLogical(expr, start); err != nil {
+			return nil, err
+		}
+
+		return notExpr{expr: expr}, nil
+	}
+
+	if p.currChar() == '(' {
+		return p.parseParenExpr()
+	}
+
+	start := p.i
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	end := p.i
+	p.skipBlanks()
+	op, ok := p.parseComparisonOp()
+	if !ok {
+		p.i = end
+		return left, nil
+	}
+	if err := p.checkComparable(left, start); err != nil {
+		return nil, err
+	}
+
+	p.skipBlanks()
+	rightStart := p.i
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkComparable(right, rightStart); err != nil {
+		return nil, err
+	}
+
+	return comparisonExpr{
+		op:    op,
+		left:  left,
+		right: right,
+	}, nil
+}
+
+func (p *queryParser) parseParenExpr() (filterExpr, error) {
+	p.readChar()
+	p.skipBlanks()
+
+	start := p.i
+	expr, err := p.parseLogicalExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkLogical(expr, start); err != nil {
+		return nil, err
+	}
+
+	p.skipBlanks()
+	if !p.consume(")") {
+		return nil, p.unexpectedCharError("invalid expression format", ")")
+	}
+
+	return parenExpr{expr: expr}, nil
+}
+
+func (p *queryParser) parseComparisonOp() (comparisonOp, bool) {
+	for _, op := range comparisonOps {
+		if p.consume(string(op)) {
+			return op, true
+		}
+	}
+
+	return "", false
+}
+
+// parseOperand parses the literal, filter query or function expression.
+func (p *queryParser) parseOperand() (filterExpr, error) {
+	switch c := p.currChar(); {
+	case c == '@' || c == '$':
+		p.readChar()
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return queryExpr{
+			absolute: c == '$',
+			segments: segments,
+		}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseStringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return literalExpr{val: String(s)}, nil
+	case c == '-' || isNum(c):
+		return p.parseNumLiteral()
+	case 'a' <= c && c <= 'z':
+		return p.parseNameOperand()
+	default:
+		return nil, p.unexpectedCharError("invalid expression format", "literal", "query", "function")
+	}
+}
+
+func (p *queryParser) parseNumLiteral() (filterExpr, error) {
+	start := p.i
+	for !p.isEOF() && isNumPart(p.currChar()) {
+		p.readChar()
+	}
+
+	n, err := NewNum(p.src[start:p.i])
+	if err != nil {
+		return nil, p.errorf(start, "%s", err)
+	}
+
+	return literalExpr{val: n}, nil
+}
+
+// parseNameOperand parses true, false, null or function expression.
+func (p *queryParser) parseNameOperand() (filterExpr, error) {
+	start := p.i
+	for !p.isEOF() {
+		c := p.currChar()
+		if c != '_' && !('a' <= c && c <= 'z') && !isNum(c) {
+			break
+		}
+		p.readChar()
+	}
+	name := p.src[start:p.i]
+
+	if p.currChar() != '(' {
+		switch name {
+		case literalTrue, literalFalse:
+			return literalExpr{val: Bool(name == literalTrue)}, nil
+		case literalNull:
+			return literalExpr{val: Null{}}, nil
+		default:
+			return nil, p.errorf(start, "invalid expression format: unknown literal %s", name)
+		}
+	}
+
+	fn, ok := queryFunctions[name]
+	if !ok {
+		return nil, p.errorf(start, "invalid function expression format: unknown function %s", name)
+	}
+
+	return p.parseFunctionExpr(fn, start)
+}
+
+func (p *queryParser) parseFunctionExpr(fn queryFunction, start int) (filterExpr, error) {
+	p.readChar()
+	p.skipBlanks()
+
+	expr := functionExpr{
+		fn: fn,
+	}
+	for !p.consume(")") {
+		if len(expr.args) != 0 {
+			if !p.consume(",") {
+				return nil, p.unexpectedCharError("invalid function expression format", ",", ")")
+			}
+			p.skipBlanks()
+		}
+
+		argStart := p.i
+		arg, err := p.parseLogicalExpr()
+		if err != nil {
+			return nil, err
+		}
+		if len(expr.args) >= len(fn.params) {
+			return nil, p.errorf(argStart, "invalid function expression format: %s() takes %d arguments", fn.name, len(fn.params))
+		}
+		if err := p.checkArg(arg, fn.params[len(expr.args)], argStart); err != nil {
+			return nil, err
+		}
+		expr.args = append(expr.args, arg)
+
+		p.skipBlanks()
+	}
+	if len(expr.args) != len(fn.params) {
+		return nil, p.errorf(start, "invalid function expression format: %s() takes %d arguments", fn.name, len(fn.params))
+	}
+	// The literal pattern is compiled only once rather than every time the function is called.
+	if lit, ok := expr.args[len(expr.args)-1].(literalExpr); ok && (fn.name == "match" || fn.name == "search") {
+		expr.args[len(expr.args)-1] = newPatternExpr(lit, fn.name == "match")
+	}
+
+	return expr, nil
+}
+
+// checkLogical checks if the expression can be tested, which is the one of LogicalType or NodesType.
+func (p *queryParser) checkLogical(expr filterExpr, at int) error {
+	switch expr := expr.(type) {
+	case literalExpr:
+		return p.errorf(at, "invalid expression format: literal cannot be tested")
+	case functionExpr:
+		if expr.exprType() == valueExprType {
+			return p.errorf(at, "invalid expression format: %s() returns %s which cannot be tested", expr.fn.name, valueExprType)
+		}
+	}
+
+	return nil
+}
+
+// checkComparable checks if the expression can be compared, which is literal, singular query
+// or function expression of ValueType.
+func (p *queryParser) checkComparable(expr filterExpr, at int) error {
+	switch expr := expr.(type) {
+	case literalExpr:
+		return nil
+	case queryExpr:
+		if !expr.isSingular() {
+			return p.errorf(at, "invalid comparison format: non singular query cannot be compared")
+		}
+		return nil
+	case functionExpr:
+		if expr.exprType() != valueExprType {
+			return p.errorf(at, "invalid comparison format: %s() returns %s which cannot be compared", expr.fn.name, expr.exprType())
+		}
+		return nil
+	default:
+		return p.errorf(at, "invalid comparison format: logical expression cannot be compared")
+	}
+}
+
+func (p *queryParser) checkArg(arg filterExpr, param exprType, at int) error {
+	switch param {
+	case valueExprType:
+		if err := p.checkComparable(arg, at); err != nil {
+			return p.errorf(at, "invalid function expression format: argument should be %s", valueExprType)
+		}
+	case logicalExprType:
+		if err := p.checkLogical(arg, at); err != nil {
+			return p.errorf(at, "invalid function expression format: argument should be %s", logicalExprType)
+		}
+	case nodesExprType:
+		if arg.exprType() != nodesExprType {
+			return p.errorf(at, "invalid function expression format: argument should be %s", nodesExprType)
+		}
+	}
+
+	return nil
+}
+
+// parseStringLiteral parses the string literal quoted by either '\” or '"'
+// converting it into the one of JSON to unquote.
+func (p *queryParser) parseStringLiteral() (string, error) {
+	start := p.i
+	quote := p.currChar()
+	p.readChar()
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for {
+		if p.isEOF() {
+			return "", p.errorf(start, "invalid string format: string should be closed by '%c'", quote)
+		}
+
+		c := p.currChar()
+		p.readChar()
+		switch {
+		case c == quote:
+			b.WriteByte('"')
+
+			unquoted, err := unquoteStringLiteral(b.String(), pos{})
+			if err != nil {
+				return "", p.errorf(start, "%s", err.Msg)
+			}
+			return unquoted, nil
+		case c == '\\' && quote == '\'' && p.currChar() == '\'':
+			p.readChar()
+			b.WriteByte('\'')
+		case c == '\\' && quote == '\'' && p.currChar() == '"':
+			return "", p.errorf(p.i-1, "invalid string format: invalid escape sequence '\\\"'")
+		case c == '\\' && !p.isEOF():
+			b.WriteByte('\\')
+			b.WriteRune(p.currChar())
+			p.readChar()
+		case c == '"':
+			b.WriteString(`\"`)
+		default:
+			b.WriteRune(c)
+		}
+	}
+}
+
+func (p *queryParser) skipBlanks() {
+	for !p.isEOF() {
+		switch p.currChar() {
+		case ' ', '\t', '\n', '\r':
+			p.readChar()
+		default:
+			return
+		}
+	}
+}
+
+func (p *queryParser) consume(s string) bool {
+	if !strings.HasPrefix(p.src[p.i:], s) {
+		return false
+	}
+
+	p.i += len(s)
+	return true
+}
+
+func (p *queryParser) readChar() {
+	_, size := utf8.DecodeRuneInString(p.src[p.i:])
+	p.i += size
+}
+
+func (p queryParser) currChar() rune {
+	if p.isEOF() {
+		return charEOF
+	}
+
+	c, _ := utf8.DecodeRuneInString(p.src[p.i:])
+	return c
+}
+
+func (p queryParser) isEOF() bool {
+	return p.i >= len(p.src)
+}
+
+func (p queryParser) unexpectedCharError(msg string, expected ...string) *SyntaxError {
+	err := p.errorf(p.i, "%s", msg)
+	err.Expected = expected
+	err.Found = string(p.currChar())
+	if p.isEOF() {
+		err.Found = string(tokenEOF)
+	}
+
+	return err
+}
+
+// errorf returns SyntaxError at the given byte index with the excerpt of the line.
+func (p queryParser) errorf(at int, format string, args ...interface{}) *SyntaxError {
+	var line lineTail
+	var curr pos
+	for i, c := range p.src {
+		if i == at {
+			curr.offset = i
+			break
+		}
+		if c == '\n' {
+			curr.line, curr.start = curr.line+1, 0
+			line.reset(curr.line)
+		} else {
+			curr.start++
+			line.append(c)
+		}
+		curr.offset = i + utf8.RuneLen(c)
+	}
+	for _, c := range p.src[curr.offset:] {
+		if c == '\n' {
+			break
+		}
+		line.append(c)
+	}
+
+	err := newSyntaxError(curr, format, args...)
+	err.Excerpt = line.excerpt(curr.start)
+
+	return err
+}
@@ -0,0 +1,374 @@
+package json
+
+import (
+	"errors"
+	"testing"
+)
+
+const testStore = `{
+	"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+		],
+		"bicycle": {"color": "red", "price": 399}
+	}
+}`
+
+func TestQuerySelect(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		query    string
+		expected []string
+	}{
+		"root": {
+			src:      `{"a": 1}`,
+			query:    "$",
+			expected: []string{"$"},
+		},
+		"shorthand names": {
+			src:      testStore,
+			query:    "$.store.bicycle.color",
+			expected: []string{"$['store']['bicycle']['color']"},
+		},
+		"bracketed names": {
+			src:      `{"o": {"j j": {"k.k": 3}}, "'": {"@": 2}}`,
+			query:    `$.o['j j']["k.k"]`,
+			expected: []string{"$['o']['j j']['k.k']"},
+		},
+		"escaped name": {
+			src:      `{"'": {"@": 2}}`,
+			query:    `$['\'']['@']`,
+			expected: []string{`$['\'']['@']`},
+		},
+		"wildcard": {
+			src:      testStore,
+			query:    "$.store.*",
+			expected: []string{"$['store']['book']", "$['store']['bicycle']"},
+		},
+		"multiple selectors": {
+			src:      `["a", "b", "c", "d"]`,
+			query:    "$[0, 3, 0]",
+			expected: []string{"$[0]", "$[3]", "$[0]"},
+		},
+		"negative index": {
+			src:      `["a", "b"]`,
+			query:    "$[-1]",
+			expected: []string{"$[1]"},
+		},
+		"index out of range": {
+			src:      `["a", "b"]`,
+			query:    "$[2]",
+			expected: []string{},
+		},
+		"slice": {
+			src:      `["a", "b", "c", "d", "e", "f", "g"]`,
+			query:    "$[1:5:2]",
+			expected: []string{"$[1]", "$[3]"},
+		},
+		"slice with negative step": {
+			src:      `["a", "b", "c", "d", "e", "f", "g"]`,
+			query:    "$[5:1:-2]",
+			expected: []string{"$[5]", "$[3]"},
+		},
+		"reversing slice": {
+			src:      `["a", "b", "c"]`,
+			query:    "$[::-1]",
+			expected: []string{"$[2]", "$[1]", "$[0]"},
+		},
+		"slice with zero step": {
+			src:      `["a", "b", "c"]`,
+			query:    "$[::0]",
+			expected: []string{},
+		},
+		"descendant names": {
+			src:      testStore,
+			query:    "$..author",
+			expected: []string{"$['store']['book'][0]['author']", "$['store']['book'][1]['author']", "$['store']['book'][2]['author']", "$['store']['book'][3]['author']"},
+		},
+		"descendant wildcard": {
+			src:      `{"o": {"j": 1, "k": 2}, "a": [5, 3]}`,
+			query:    "$..*",
+			expected: []string{"$['o']", "$['a']", "$['o']['j']", "$['o']['k']", "$['a'][0]", "$['a'][1]"},
+		},
+		"descendant index": {
+			src:      `{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`,
+			query:    "$..[0]",
+			expected: []string{"$['a'][0]", "$['a'][2][0]"},
+		},
+		"filter with comparison": {
+			src:      testStore,
+			query:    "$.store.book[?@.price < 10].title",
+			expected: []string{"$['store']['book'][0]['title']", "$['store']['book'][2]['title']"},
+		},
+		"filter with parenthesized comparison": {
+			src:      `{"items": [{"id": 1, "status": "failed"}, {"id": 2, "status": "ok"}, {"id": 3, "status": "failed"}]}`,
+			query:    `$.items[?(@.status=="failed")].id`,
+			expected: []string{"$['items'][0]['id']", "$['items'][2]['id']"},
+		},
+		"filter with existence": {
+			src:      testStore,
+			query:    "$..book[?@.isbn]",
+			expected: []string{"$['store']['book'][2]", "$['store']['book'][3]"},
+		},
+		"filter with negated existence": {
+			src:      testStore,
+			query:    "$..book[?!@.isbn]",
+			expected: []string{"$['store']['book'][0]", "$['store']['book'][1]"},
+		},
+		"filter with logical operators": {
+			src:      testStore,
+			query:    `$.store.book[?@.price > 20 || (@.category == "reference" && !(@.price > 10))]`,
+			expected: []string{"$['store']['book'][0]", "$['store']['book'][3]"},
+		},
+		"filter with absolute query": {
+			src:      testStore,
+			query:    "$..book[?@.price <= $.store.book[0].price]",
+			expected: []string{"$['store']['book'][0]"},
+		},
+		"filter comparing numbers numerically": {
+			src:      `[{"a": 1}, {"a": 1.0}, {"a": 10e-1}, {"a": "1"}]`,
+			query:    "$[?@.a == 1]",
+			expected: []string{"$[0]", "$[1]", "$[2]"},
+		},
+		"filter comparing structures": {
+			src:      `[{"a": {"x": [1, 2], "y": null}}, {"a": {"y": null, "x": [1, 2]}}, {"a": {"x": [2, 1]}}]`,
+			query:    "$[?@.a == $[0].a]",
+			expected: []string{"$[0]", "$[1]"},
+		},
+		"filter comparing nothing": {
+			src:      `[{"a": 1}, {"b": 2}]`,
+			query:    "$[?@.a == @.c]",
+			expected: []string{"$[1]"},
+		},
+		"filter on object": {
+			src:      `{"a": {"x": 1}, "b": {"x": 2}}`,
+			query:    "$[?@.x > 1]",
+			expected: []string{"$['b']"},
+		},
+		"length": {
+			src:      `[{"a": "ab"}, {"a": "あいう"}, {"a": [1, 2, 3]}, {"a": 3}]`,
+			query:    "$[?length(@.a) >= 3]",
+			expected: []string{"$[1]", "$[2]"},
+		},
+		"count": {
+			src:      `[{"a": [1, 2]}, {"a": [1]}, {"b": {"c": 1, "d": 2}}]`,
+			query:    "$[?count(@.*.*) == 2]",
+			expected: []string{"$[0]", "$[2]"},
+		},
+		"match": {
+			src:      `[{"d": "1974-05-11"}, {"d": "1974-05-11T00:00"}, {"d": 1974}]`,
+			query:    `$[?match(@.d, "1974-05-..")]`,
+			expected: []string{"$[0]"},
+		},
+		"search": {
+			src:      `[{"d": "1974-05-11"}, {"d": "1974-05-11T00:00"}, {"d": "1975"}]`,
+			query:    `$[?search(@.d, '05-..')]`,
+			expected: []string{"$[0]", "$[1]"},
+		},
+		"dot of regexp not matching line break": {
+			src:      `["a\nb", "a b"]`,
+			query:    `$[?match(@, 'a.b')]`,
+			expected: []string{"$[1]"},
+		},
+		"pattern from document": {
+			src:      `[{"s": "abc", "p": "a.c"}, {"s": "abc", "p": "b"}, {"s": "abc", "p": 1}]`,
+			query:    `$[?match(@.s, @.p)]`,
+			expected: []string{"$[0]"},
+		},
+		"invalid literal pattern": {
+			src:      `["a", "("]`,
+			query:    `$[?search(@, '(')]`,
+			expected: []string{},
+		},
+		"value": {
+			src:      `[{"a": [{"b": 1}]}, {"a": [{"b": 1}, {"b": 2}]}]`,
+			query:    "$[?value(@.a[*].b) == 1]",
+			expected: []string{"$[0]"},
+		},
+		"blanks": {
+			src:      `{"a": [1, 2]}`,
+			query:    "$ .a [ 0 , 1 ]",
+			expected: []string{"$['a'][0]", "$['a'][1]"},
+		},
+		"name with control character": {
+			src:      `{"a\u0001b": 1}`,
+			query:    "$.*",
+			expected: []string{`$['a\u0001b']`},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			root, err := ParseString(test.src)
+			if err != nil {
+				t.Errorf("should have parsed the source: %s", err)
+				return
+			}
+			q, err := ParseQuery(test.query)
+			if err != nil {
+				t.Errorf("should have parsed the query: %s", err)
+				return
+			}
+
+			matches := q.Select(root)
+			actual := make([]string, len(matches))
+			for i, m := range matches {
+				actual[i] = m.Path()
+			}
+			if err := assertPaths(actual, test.expected); err != nil {
+				t.Errorf("should have selected: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	root, err := ParseString(`{"a": [{"b/c": "d"}]}`)
+	if err != nil {
+		t.Errorf("should have parsed the source: %s", err)
+		return
+	}
+	q, err := ParseQuery("$.a[0]['b/c']")
+	if err != nil {
+		t.Errorf("should have parsed the query: %s", err)
+		return
+	}
+
+	matches := q.Select(root)
+	if len(matches) != 1 {
+		t.Errorf("should have selected: %s", reportUnexpected("len of matches", len(matches), 1))
+		return
+	}
+	if err := assertValue(matches[0].Value, String("d")); err != nil {
+		t.Errorf("should have selected: %s", err)
+		return
+	}
+	if ptr := matches[0].Pointer().String(); ptr != "/a/0/b~1c" {
+		t.Errorf("should have returned the pointer: %s", reportUnexpected("pointer", ptr, "/a/0/b~1c"))
+		return
+	}
+}
+
+func TestParseInvalidQuery(t *testing.T) {
+	tests := map[string]struct {
+		query    string
+		expected SyntaxError
+	}{
+		"without root": {
+			query:    "a.b",
+			expected: SyntaxError{Line: 1, Column: 1, Offset: 0},
+		},
+		"leading blank": {
+			query:    " $.a",
+			expected: SyntaxError{Line: 1, Column: 1, Offset: 0},
+		},
+		"trailing blank": {
+			query:    "$.a ",
+			expected: SyntaxError{Line: 1, Column: 4, Offset: 3},
+		},
+		"unclosed bracket": {
+			query:    "$['a'",
+			expected: SyntaxError{Line: 1, Column: 6, Offset: 5},
+		},
+		"unclosed string": {
+			query:    "$['a]",
+			expected: SyntaxError{Line: 1, Column: 3, Offset: 2},
+		},
+		"invalid escape": {
+			query:    `$["\'"]`,
+			expected: SyntaxError{Line: 1, Column: 3, Offset: 2},
+		},
+		"leading zero": {
+			query:    "$[01]",
+			expected: SyntaxError{Line: 1, Column: 3, Offset: 2},
+		},
+		"negative zero": {
+			query:    "$[-0]",
+			expected: SyntaxError{Line: 1, Column: 3, Offset: 2},
+		},
+		"index out of range": {
+			query:    "$[9007199254740992]",
+			expected: SyntaxError{Line: 1, Column: 3, Offset: 2},
+		},
+		"name starting with digit": {
+			query:    "$.1a",
+			expected: SyntaxError{Line: 1, Column: 3, Offset: 2},
+		},
+		"literal as test": {
+			query:    "$[?1]",
+			expected: SyntaxError{Line: 1, Column: 4, Offset: 3},
+		},
+		"non singular query in comparison": {
+			query:    "$[?@.* == 1]",
+			expected: SyntaxError{Line: 1, Column: 4, Offset: 3},
+		},
+		"comparison of logical expression": {
+			query:    "$[?(@.a) == 1]",
+			expected: SyntaxError{Line: 1, Column: 10, Offset: 9},
+		},
+		"value function as test": {
+			query:    "$[?length(@.a)]",
+			expected: SyntaxError{Line: 1, Column: 4, Offset: 3},
+		},
+		"logical function in comparison": {
+			query:    "$[?match(@.a, 'a') == true]",
+			expected: SyntaxError{Line: 1, Column: 4, Offset: 3},
+		},
+		"non singular query as value argument": {
+			query:    "$[?length(@.*) == 1]",
+			expected: SyntaxError{Line: 1, Column: 11, Offset: 10},
+		},
+		"literal as nodes argument": {
+			query:    "$[?count(1) == 1]",
+			expected: SyntaxError{Line: 1, Column: 10, Offset: 9},
+		},
+		"too few arguments": {
+			query:    "$[?match(@.a)]",
+			expected: SyntaxError{Line: 1, Column: 4, Offset: 3},
+		},
+		"unknown function": {
+			query:    "$[?foo(@.a)]",
+			expected: SyntaxError{Line: 1, Column: 4, Offset: 3},
+		},
+		"in second line": {
+			query:    "$[?@.a ==\n  ]",
+			expected: SyntaxError{Line: 2, Column: 3, Offset: 12},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			_, err := ParseQuery(test.query)
+			var actual *SyntaxError
+			if !errors.As(err, &actual) {
+				t.Errorf("should have returned SyntaxError: %v", err)
+				return
+			}
+			if actual.Line != test.expected.Line || actual.Column != test.expected.Column || actual.Offset != test.expected.Offset {
+				t.Errorf("should have returned SyntaxError: %s", reportUnexpected(
+					"position",
+					[]int{actual.Line, actual.Column, actual.Offset},
+					[]int{test.expected.Line, test.expected.Column, test.expected.Offset},
+				))
+				return
+			}
+		})
+	}
+}
+
+func assertPaths(actual, expected []string) error {
+	if len(actual) != len(expected) {
+		return reportUnexpected("paths", actual, expected)
+	}
+	for i, expected := range expected {
+		if actual[i] != expected {
+			return reportUnexpected("path", actual[i], expected)
+		}
+	}
+
+	return nil
+}
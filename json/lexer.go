@@ -1,31 +1,90 @@
 package json
 
-func newLexer(src []rune) lexer {
-	return lexer{
-		src: src,
+import (
+	"bufio"
+	"io"
+	"unicode"
+
+	"github.com/tomocy/go-cookbook/internal/syntaxerr"
+)
+
+// lexerBufSize is the size of the buffer through which the lexer reads its source.
+// The memory which the lexer uses stays around this size however large the source is,
+// except for the literal of the token being read.
+const lexerBufSize = 4096
+
+func newLexer(src io.Reader) lexer {
+	l := lexer{
+		src: bufio.NewReaderSize(src, lexerBufSize),
 	}
+	l.next, l.nextSize, l.nextEOF = l.readRune()
+
+	return l
 }
 
 type lexer struct {
-	src       []rune
-	currIndex int
-	nextIndex int
-	pos       pos
+	src                *bufio.Reader
+	curr, next         rune
+	currSize, nextSize int
+	// currEOF and nextEOF tell the end of the source apart from NUL character in the source.
+	currEOF, nextEOF bool
+	currIndex        int
+	nextIndex        int
+	pos              pos
+	literal          []rune
+	err              error
+
+	// line keeps the tail of the line being read and tokenLine keeps the tail of
+	// the last line which has been read and has a token so that errors can show excerpts of them.
+	line         lineTail
+	tokenLine    lineTail
+	tokenLineNum int
+
+	dialect Dialect
+	// maxStringLength is the max number of characters of strings after they are unescaped if it is positive.
+	maxStringLength int
 }
 
 const (
-	charEOF = 0
+	charEOF rune = 0
 
 	literalTrue  = "true"
 	literalFalse = "false"
+	literalNull  = "null"
+
+	literalInfinity = "Infinity"
+	literalNaN      = "NaN"
 )
 
 func (l *lexer) readToken() token {
+	t := l.composeToken()
+	// The current character is the last one of the token.
+	t.endOffset = l.pos.offset + l.currSize
+
+	return t
+}
+
+func (l *lexer) composeToken() token {
 	l.readChar()
-	l.skipWhitespaces()
+	if commentPos, ok := l.skipWhitespaces(); !ok {
+		return token{
+			kind:    tokenIllegal,
+			literal: "/*",
+			pos:     commentPos,
+		}
+	}
+	l.tokenLineNum = l.pos.line
 
 	switch char := l.currChar(); char {
 	case charEOF:
+		if !l.currEOF {
+			return token{
+				kind:    tokenIllegal,
+				literal: string(char),
+				pos:     l.pos,
+			}
+		}
+
 		return token{
 			kind:    tokenKinds[string(char)],
 			literal: "",
@@ -40,59 +99,178 @@ func (l *lexer) readToken() token {
 	case '"':
 		return l.composeString()
 	default:
-		if isNum(char) {
+		if isNum(char) || char == '-' {
 			return l.composeNum()
 		}
+		if l.dialect == DialectJSON5 {
+			switch {
+			case char == '\'':
+				return l.composeString()
+			case char == '+' || char == '.':
+				return l.composeNum()
+			case isJSON5IdentifierStart(char):
+				return l.composeIdentifier()
+			}
+		}
 		if isLetter(char) {
 			return l.composeLetters()
 		}
 
 		return token{
-			kind: tokenIllegal,
-			pos:  l.pos,
+			kind:    tokenIllegal,
+			literal: string(char),
+			pos:     l.pos,
+		}
+	}
+}
+
+// skipWhitespaces skips whitespaces and comments if the dialect allows them.
+// It returns false with the pos of the comment if the comment is not terminated.
+func (l *lexer) skipWhitespaces() (pos, bool) {
+	for {
+		c := l.currChar()
+		switch {
+		case isWhitespace(c), l.dialect == DialectJSON5 && isJSON5Whitespace(c):
+			l.readChar()
+		case c == '/' && l.dialect != DialectJSON && (l.nextChar() == '/' || l.nextChar() == '*'):
+			start := l.pos
+			if !l.skipComment() {
+				return start, false
+			}
+		default:
+			return pos{}, true
 		}
 	}
 }
 
-func (l *lexer) skipWhitespaces() {
-	for isWhitespace(l.currChar()) {
+// skipComment skips the line comment or the block comment which starts with the current character,
+// and reports whether the comment is terminated.
+func (l *lexer) skipComment() bool {
+	l.readChar()
+	if l.currChar() == '/' {
+		for !l.currEOF && l.currChar() != '\n' {
+			l.readChar()
+		}
+		return true
+	}
+
+	l.readChar()
+	for !l.currEOF {
+		if l.currChar() == '*' && l.nextChar() == '/' {
+			l.readChar()
+			l.readChar()
+			return true
+		}
 		l.readChar()
 	}
+
+	return false
 }
 
 func (l *lexer) composeString() token {
 	t := token{
 		kind: tokenString,
 		pos: pos{
-			line:  l.pos.line,
-			start: l.pos.start,
+			line:   l.pos.line,
+			start:  l.pos.start,
+			offset: l.pos.offset,
 		},
 	}
-	t.literal = l.readString()
+	t.literal, t.tooLong = l.readString()
 	t.pos.end = l.pos.end
 
 	return t
 }
 
-func (l *lexer) readString() string {
-	start := l.currIndex
+// readString reads the string literal, and reports whether the string is longer than the max after it is unescaped.
+// The rest of the too long string is skipped without being kept so that it does not take up memory.
+func (l *lexer) readString() (string, bool) {
+	quote := l.currChar()
+	l.startLiteral()
+	// n is the number of the characters which have been read after they are unescaped.
+	n := 0
+	tooLong := false
+	// keeps appends the current character to the literal unless the string turns out to be too long.
+	keep := func() {
+		if !tooLong {
+			l.appendLiteral()
+		}
+	}
+	// afterHigh tells that the last escape sequence is the high surrogate,
+	// which makes one character with the low surrogate following it.
+	afterHigh := false
 	for {
 		l.readChar()
+		if l.currEOF {
+			break
+		}
+		keep()
+
+		if l.currChar() == quote {
+			break
+		}
+		if l.currChar() != '\\' || l.nextEOF {
+			n++
+			afterHigh = false
+		} else {
+			l.readChar()
+			keep()
+			switch c := l.currChar(); {
+			case c == 'u':
+				r := l.readHexDigits(4, keep)
+				if !afterHigh || r < 0xdc00 || 0xdfff < r {
+					n++
+				}
+				afterHigh = 0xd800 <= r && r < 0xdc00
+			case l.dialect == DialectJSON5 && c == 'x':
+				l.readHexDigits(2, keep)
+				n++
+				afterHigh = false
+			case l.dialect == DialectJSON5 && (c == '\n' || c == '\r' || c == '\u2028' || c == '\u2029'):
+				// The line continuation makes no character including \r\n.
+				if c == '\r' && l.nextChar() == '\n' {
+					l.readChar()
+					keep()
+				}
+				afterHigh = false
+			default:
+				n++
+				afterHigh = false
+			}
+		}
+
+		if max := l.maxStringLength; max > 0 && n > max {
+			tooLong = true
+		}
+	}
 
-		if l.currChar() == charEOF || l.currChar() == '"' {
+	return string(l.literal), tooLong
+}
+
+// readHexDigits reads at most the given number of the hex digits which follow the current character
+// calling keep for each of them, and returns the value of them.
+func (l *lexer) readHexDigits(digits int, keep func()) rune {
+	var r rune
+	for i := 0; i < digits; i++ {
+		d, ok := hexDigit(l.nextChar())
+		if !ok {
 			break
 		}
+		l.readChar()
+		keep()
+		r = r<<4 | d
 	}
 
-	return string(l.src[start:l.nextIndex])
+	return r
 }
 
 func (l *lexer) composeNum() token {
 	t := token{
 		kind: tokenNum,
 		pos: pos{
-			line:  l.pos.line,
-			start: l.pos.start,
+			line:   l.pos.line,
+			start:  l.pos.start,
+			offset: l.pos.offset,
 		},
 	}
 	t.literal = l.readNumber()
@@ -102,20 +280,21 @@ func (l *lexer) composeNum() token {
 }
 
 func (l *lexer) readNumber() string {
-	start := l.currIndex
-
-	for isNum(l.nextChar()) {
+	l.startLiteral()
+	for isNumPart(l.nextChar()) || l.dialect == DialectJSON5 && isJSON5NumPart(l.nextChar()) {
 		l.readChar()
+		l.appendLiteral()
 	}
 
-	return string(l.src[start:l.nextIndex])
+	return string(l.literal)
 }
 
 func (l *lexer) composeLetters() token {
 	t := token{
 		pos: pos{
-			line:  l.pos.line,
-			start: l.pos.start,
+			line:   l.pos.line,
+			start:  l.pos.start,
+			offset: l.pos.offset,
 		},
 	}
 	t.literal = l.readLetters()
@@ -131,46 +310,191 @@ func (l *lexer) composeLetters() token {
 }
 
 func (l *lexer) readLetters() string {
-	start := l.currIndex
-
+	l.startLiteral()
 	for isLetter(l.nextChar()) {
 		l.readChar()
+		l.appendLiteral()
+	}
+
+	return string(l.literal)
+}
+
+// composeIdentifier composes the token of the identifier of JSON5 such as the unquoted key,
+// which may be the literals such as true and Infinity.
+func (l *lexer) composeIdentifier() token {
+	t := token{
+		pos: pos{
+			line:   l.pos.line,
+			start:  l.pos.start,
+			offset: l.pos.offset,
+		},
+	}
+	l.startLiteral()
+	for isJSON5IdentifierPart(l.nextChar()) {
+		l.readChar()
+		l.appendLiteral()
+	}
+	t.literal = string(l.literal)
+	t.pos.end = l.pos.end
+
+	switch t.literal {
+	case literalInfinity, literalNaN:
+		t.kind = tokenNum
+	case literalTrue, literalFalse, literalNull:
+		t.kind = tokenKinds[t.literal]
+	default:
+		t.kind = tokenIdentifier
 	}
 
-	return string(l.src[start:l.nextIndex])
+	return t
+}
+
+func (l *lexer) startLiteral() {
+	l.literal = append(l.literal[:0], l.currChar())
+}
+
+func (l *lexer) appendLiteral() {
+	l.literal = append(l.literal, l.currChar())
 }
 
 func (l *lexer) readChar() {
-	if l.nextIndex > len(l.src) {
+	if l.currEOF {
 		return
 	}
 
+	l.pos.offset += l.currSize
+
+	l.curr, l.currSize, l.currEOF = l.next, l.nextSize, l.nextEOF
 	l.currIndex = l.nextIndex
 	l.nextIndex++
 
+	if !l.currEOF {
+		l.next, l.nextSize, l.nextEOF = l.readRune()
+	}
+
+	l.trackLine()
 	l.pos.move(l.currChar())
 }
 
+func (l *lexer) readRune() (rune, int, bool) {
+	c, size, err := l.src.ReadRune()
+	if err != nil {
+		if err != io.EOF {
+			l.err = err
+		}
+		return charEOF, 0, true
+	}
+
+	return c, size, false
+}
+
+func (l *lexer) trackLine() {
+	if l.currEOF {
+		return
+	}
+	if l.currChar() != '\n' {
+		l.line.append(l.currChar())
+		return
+	}
+
+	if l.tokenLineNum == l.pos.line {
+		l.tokenLine.keep(l.line)
+	}
+	l.line.reset(l.pos.line + 1)
+}
+
+// excerpt returns the line where the given pos is with a caret under the pos.
+// It returns empty string if the line has already gone.
+func (l *lexer) excerpt(at pos) string {
+	var line lineTail
+	switch at.line {
+	case l.pos.line:
+		line.keep(l.line)
+		line.chars = append(line.chars, l.peekRestOfLine()...)
+	case l.tokenLine.num:
+		line = l.tokenLine
+	default:
+		return ""
+	}
+
+	return line.excerpt(at.start)
+}
+
+func (l *lexer) peekRestOfLine() []rune {
+	if l.nextEOF || l.next == '\n' {
+		return nil
+	}
+
+	rest := []rune{l.next}
+	buffered, _ := l.src.Peek(l.src.Buffered())
+	for _, c := range string(buffered) {
+		if c == '\n' || len(rest) >= excerptWidth {
+			break
+		}
+		rest = append(rest, c)
+	}
+
+	return rest
+}
+
+// excerptWidth is the number of characters around the column which excerpts show.
+const excerptWidth = syntaxerr.ExcerptWidth
+
+// lineTail keeps at most the last 2 * excerptWidth characters of a line
+// so that a long line such as minified JSON does not grow the memory.
+type lineTail struct {
+	num   int
+	start int
+	chars []rune
+}
+
+func (t *lineTail) append(c rune) {
+	if len(t.chars) >= 2*excerptWidth {
+		t.chars = append(t.chars[:0], t.chars[excerptWidth:]...)
+		t.start += excerptWidth
+	}
+
+	t.chars = append(t.chars, c)
+}
+
+func (t *lineTail) keep(src lineTail) {
+	t.num, t.start = src.num, src.start
+	t.chars = append(t.chars[:0], src.chars...)
+}
+
+func (t *lineTail) reset(num int) {
+	t.num, t.start = num, 0
+	t.chars = t.chars[:0]
+}
+
+func (t lineTail) excerpt(col int) string {
+	return syntaxerr.Excerpt(t.chars, col-t.start)
+}
+
 func (l lexer) currChar() rune {
-	if l.currIndex >= len(l.src) {
+	if l.currEOF {
 		return charEOF
 	}
 
-	return l.src[l.currIndex]
+	return l.curr
 }
 
 func (l lexer) nextChar() rune {
-	if l.nextIndex >= len(l.src) {
+	if l.nextEOF {
 		return charEOF
 	}
 
-	return l.src[l.nextIndex]
+	return l.next
 }
 
 func isNum(c rune) bool {
 	return '0' <= c && c <= '9'
 }
 
+func isNumPart(c rune) bool {
+	return isNum(c) || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E'
+}
+
 func isLetter(c rune) bool {
 	return 'a' <= c && c <= 'z'
 }
@@ -179,10 +503,48 @@ func isWhitespace(c rune) bool {
 	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
 }
 
+func isJSON5Whitespace(c rune) bool {
+	switch c {
+	case '\v', '\f', '\u00a0', '\u2028', '\u2029', '\ufeff':
+		return true
+	default:
+		return unicode.Is(unicode.Zs, c)
+	}
+}
+
+// isJSON5NumPart reports whether the character is a part of the numbers of JSON5
+// in addition to the ones of JSON, which are such as 0x1F, Infinity and NaN.
+func isJSON5NumPart(c rune) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isJSON5IdentifierStart(c rune) bool {
+	return c == '$' || c == '_' || unicode.IsLetter(c)
+}
+
+func isJSON5IdentifierPart(c rune) bool {
+	return isJSON5IdentifierStart(c) || unicode.IsDigit(c) || unicode.In(c, unicode.Mn, unicode.Mc, unicode.Pc) ||
+		c == '\u200c' || c == '\u200d'
+}
+
+func isJSON5Identifier(s string) bool {
+	for i, c := range s {
+		if i == 0 && !isJSON5IdentifierStart(c) || !isJSON5IdentifierPart(c) {
+			return false
+		}
+	}
+
+	return s != ""
+}
+
 type token struct {
 	kind    tokenKind
 	literal string
 	pos     pos
+	// endOffset is the byte offset of the end of the token from the beginning of the source.
+	endOffset int
+	// tooLong tells that the string is longer than the max, whose literal has only the head of it.
+	tooLong bool
 }
 
 type tokenKind string
@@ -201,8 +563,16 @@ const (
 	tokenNum    tokenKind = "number"
 	tokenString tokenKind = "string"
 	tokenBool   tokenKind = "bool"
+	tokenNull   tokenKind = "null"
+
+	// tokenIdentifier is the unquoted key of JSON5.
+	tokenIdentifier tokenKind = "identifier"
 )
 
+var valueTokenKinds = []tokenKind{
+	tokenLBracket, tokenLBrace, tokenNum, tokenString, tokenBool, tokenNull,
+}
+
 var tokenKinds = map[string]tokenKind{
 	string(charEOF): tokenEOF,
 	"[":             tokenLBracket,
@@ -213,11 +583,25 @@ var tokenKinds = map[string]tokenKind{
 	":":             tokenColon,
 	"true":          tokenBool,
 	"false":         tokenBool,
+	"null":          tokenNull,
 }
 
 type pos struct {
 	line       int
 	start, end int
+	// offset is the byte offset of start from the beginning of the source.
+	offset int
+}
+
+// shift returns the pos of the character which is the given number of characters
+// and bytes after p in the same line.
+func (p pos) shift(chars, bytes int) pos {
+	return pos{
+		line:   p.line,
+		start:  p.start + chars,
+		end:    p.start + chars + 1,
+		offset: p.offset + bytes,
+	}
 }
 
 func (p *pos) move(c rune) {
@@ -1,8 +1,13 @@
 package json
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"runtime"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestReadToken(t *testing.T) {
@@ -29,6 +34,13 @@ func TestReadToken(t *testing.T) {
 				{kind: tokenEOF, pos: pos{line: 0, start: 1, end: 2}},
 			},
 		},
+		"negative number with fraction and exponent": {
+			src: "-1.5e+10",
+			expected: []token{
+				{kind: tokenNum, literal: "-1.5e+10", pos: pos{line: 0, start: 0, end: 8}},
+				{kind: tokenEOF, pos: pos{line: 0, start: 8, end: 9}},
+			},
+		},
 		"string": {
 			src: `"aiueo01234"`,
 			expected: []token{
@@ -36,6 +48,20 @@ func TestReadToken(t *testing.T) {
 				{kind: tokenEOF, pos: pos{line: 0, start: 12, end: 13}},
 			},
 		},
+		"string with escapes": {
+			src: `"a\"b\\c\u00e9"`,
+			expected: []token{
+				{kind: tokenString, literal: `"a\"b\\c\u00e9"`, pos: pos{line: 0, start: 0, end: 15}},
+				{kind: tokenEOF, pos: pos{line: 0, start: 15, end: 16}},
+			},
+		},
+		"unterminated string": {
+			src: `"aiueo`,
+			expected: []token{
+				{kind: tokenString, literal: `"aiueo`, pos: pos{line: 0, start: 0, end: 7}},
+				{kind: tokenEOF, pos: pos{line: 0, start: 6, end: 7}},
+			},
+		},
 		"true": {
 			src: "true",
 			expected: []token{
@@ -50,6 +76,13 @@ func TestReadToken(t *testing.T) {
 				{kind: tokenEOF, pos: pos{line: 0, start: 5, end: 6}},
 			},
 		},
+		"null": {
+			src: "null",
+			expected: []token{
+				{kind: tokenNull, literal: "null", pos: pos{line: 0, start: 0, end: 4}},
+				{kind: tokenEOF, pos: pos{line: 0, start: 4, end: 5}},
+			},
+		},
 		"empty array": {
 			src: "[]",
 			expected: []token{
@@ -230,7 +263,7 @@ false
 
 	for n, test := range tests {
 		t.Run(n, func(t *testing.T) {
-			lex := newLexer([]rune(test.src))
+			lex := newLexer(strings.NewReader(test.src))
 
 			for _, expected := range test.expected {
 				actual := lex.readToken()
@@ -242,6 +275,171 @@ false
 	}
 }
 
+func TestReadTokenBeyondBuffer(t *testing.T) {
+	long := strings.Repeat("a", lexerBufSize*3)
+	src := fmt.Sprintf(`["%s", %s]`, long, strings.Repeat("1", lexerBufSize))
+	expected := []token{
+		{kind: tokenLBracket, literal: "[", pos: pos{line: 0, start: 0, end: 1}},
+		{kind: tokenString, literal: `"` + long + `"`, pos: pos{line: 0, start: 1, end: len(long) + 3}},
+		{kind: tokenComma, literal: ",", pos: pos{line: 0, start: len(long) + 3, end: len(long) + 4}},
+		{kind: tokenNum, literal: strings.Repeat("1", lexerBufSize), pos: pos{line: 0, start: len(long) + 5, end: len(long) + 5 + lexerBufSize}},
+		{kind: tokenRBracket, literal: "]", pos: pos{line: 0, start: len(long) + 5 + lexerBufSize, end: len(long) + 6 + lexerBufSize}},
+	}
+
+	lex := newLexer(strings.NewReader(src))
+	for _, expected := range expected {
+		actual := lex.readToken()
+		if err := assertToken(actual, expected); err != nil {
+			t.Errorf("should have read: unexpected token: %s", err)
+			return
+		}
+	}
+}
+
+func TestReadTooLongString(t *testing.T) {
+	tests := map[string]struct {
+		dialect  Dialect
+		src      string
+		max      int
+		expected bool
+	}{
+		"at most max": {
+			src: `"abc"`,
+			max: 3,
+		},
+		"longer than max": {
+			src:      `"abcd"`,
+			max:      3,
+			expected: true,
+		},
+		"escapes": {
+			src: `"\n\u0041\ud83d\ude00"`,
+			max: 3,
+		},
+		"escapes longer than max": {
+			src:      `"\n\u0041\ud83d\ude00\t"`,
+			max:      3,
+			expected: true,
+		},
+		"lone low surrogates": {
+			src:      `"\ude00\ude00\ude00\ude00"`,
+			max:      3,
+			expected: true,
+		},
+		"json5 escapes": {
+			dialect: DialectJSON5,
+			src:     "'\\x41\\\r\nb\\\nc'",
+			max:     3,
+		},
+		"huge string": {
+			src:      `"` + strings.Repeat("a", lexerBufSize*3) + `"`,
+			max:      3,
+			expected: true,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			lex := newLexer(strings.NewReader(test.src + ","))
+			lex.dialect, lex.maxStringLength = test.dialect, test.max
+
+			actual := lex.readToken()
+			if actual.kind != tokenString || actual.tooLong != test.expected {
+				t.Errorf("should have read string: %s", reportUnexpected("too long", actual.tooLong, test.expected))
+				return
+			}
+			if actual.tooLong && len(actual.literal) > (test.max+1)*12+1 {
+				t.Errorf("should not have kept the too long string: %s", reportUnexpected("len of literal", len(actual.literal), (test.max+1)*12+1))
+				return
+			}
+			if next := lex.readToken(); next.kind != tokenComma {
+				t.Errorf("should have skipped the rest of the string: %s", reportUnexpected("kind", next.kind, tokenComma))
+				return
+			}
+		})
+	}
+}
+
+func TestReadTokenWithReadError(t *testing.T) {
+	expected := errors.New("broken")
+	lex := newLexer(io.MultiReader(strings.NewReader("[1, "), iotest.ErrReader(expected)))
+
+	for i := 0; i < 4; i++ {
+		lex.readToken()
+	}
+	if actual := lex.readToken(); actual.kind != tokenEOF {
+		t.Errorf("should have read EOF: %s", reportUnexpected("kind", actual.kind, tokenEOF))
+		return
+	}
+	if lex.err != expected {
+		t.Errorf("should have kept read error: %s", reportUnexpected("err", lex.err, expected))
+		return
+	}
+}
+
+func BenchmarkReadToken(b *testing.B) {
+	for _, size := range []int{1 << 20, 1 << 24, 1 << 26} {
+		b.Run(fmt.Sprintf("%dMB", size>>20), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(size))
+
+			var maxHeap uint64
+			for i := 0; i < b.N; i++ {
+				lex := newLexer(newRepeatedReader(`{"id": 12345, "name": "aiueo", "tags": [true, false, null]},`, size))
+				for n := 0; ; n++ {
+					if lex.readToken().kind == tokenEOF {
+						break
+					}
+					if n%(1<<16) != 0 {
+						continue
+					}
+
+					var stats runtime.MemStats
+					runtime.ReadMemStats(&stats)
+					if stats.HeapInuse > maxHeap {
+						maxHeap = stats.HeapInuse
+					}
+				}
+			}
+
+			b.ReportMetric(float64(maxHeap), "max-heap-B")
+		})
+	}
+}
+
+// repeatedReader reads the given unit repeatedly up to the given size
+// without materializing the whole source in memory.
+type repeatedReader struct {
+	unit      string
+	rest, off int
+}
+
+func newRepeatedReader(unit string, size int) *repeatedReader {
+	return &repeatedReader{
+		unit: unit,
+		rest: size,
+	}
+}
+
+func (r *repeatedReader) Read(p []byte) (int, error) {
+	if r.rest <= 0 {
+		return 0, io.EOF
+	}
+
+	var n int
+	for n < len(p) && r.rest > 0 {
+		copied := copy(p[n:], r.unit[r.off:])
+		if copied > r.rest {
+			copied = r.rest
+		}
+		n += copied
+		r.rest -= copied
+		r.off = (r.off + copied) % len(r.unit)
+	}
+
+	return n, nil
+}
+
 func TestReadChar(t *testing.T) {
 	src := "aaaaa"
 	expected := []struct {
@@ -256,9 +454,7 @@ func TestReadChar(t *testing.T) {
 		{currPos: 5, nextPos: 6},
 	}
 
-	lex := lexer{
-		src: []rune(src),
-	}
+	lex := newLexer(strings.NewReader(src))
 
 	for _, expected := range expected {
 		lex.readChar()
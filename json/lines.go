@@ -0,0 +1,123 @@
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+func NewLinesDecoder(r io.Reader) *LinesDecoder {
+	return &LinesDecoder{
+		r: bufio.NewReader(r),
+	}
+}
+
+// LinesDecoder decodes JSON Lines, also known as NDJSON, where each line is a JSON value.
+// Blank lines are ignored.
+type LinesDecoder struct {
+	r *bufio.Reader
+	// line is the number of the lines which have been read, and offset is the number of their bytes.
+	line, offset  int
+	skipsBadLines bool
+	skipped       int
+}
+
+// SetSkipBadLines makes the decoder skip the lines which are not valid JSON values
+// instead of returning errors. The number of the skipped lines is reported by Skipped.
+func (d *LinesDecoder) SetSkipBadLines(skip bool) {
+	d.skipsBadLines = skip
+}
+
+// Skipped returns the number of the lines which have been skipped as they are not valid JSON values.
+func (d *LinesDecoder) Skipped() int {
+	return d.skipped
+}
+
+// Line returns the 1-based number of the line from which the last value has been decoded.
+func (d *LinesDecoder) Line() int {
+	return d.line
+}
+
+// Decode decodes the value in the next line into v.
+// It returns io.EOF when there is no more line to decode.
+//
+// The error for an invalid line is SyntaxError whose Line and Offset are the ones in the whole source,
+// and the decoder can go on to the next line after it.
+func (d *LinesDecoder) Decode(v *Value) error {
+	for {
+		line, err := d.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read: %w", err)
+		}
+		if len(line) == 0 && err == io.EOF {
+			return io.EOF
+		}
+
+		d.line++
+		lineOffset := d.offset
+		d.offset += len(line)
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		// The line break is trimmed so that the errors at the end of the line are reported in the line.
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+		decoded, err := ParserOptions{DisallowTrailingData: true}.Parse(line)
+		if err != nil {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				return err
+			}
+			if d.skipsBadLines {
+				d.skipped++
+				continue
+			}
+
+			syntaxErr.Line += d.line - 1
+			syntaxErr.Offset += lineOffset
+
+			return fmt.Errorf("failed to decode line %d: %w", d.line, err)
+		}
+
+		*v = decoded
+
+		return nil
+	}
+}
+
+func NewLinesEncoder(w io.Writer) *LinesEncoder {
+	return &LinesEncoder{
+		enc: Encoder{
+			w: w,
+		},
+	}
+}
+
+// LinesEncoder writes JSON values in the form of JSON Lines, where each value is written in compact form in a line.
+type LinesEncoder struct {
+	enc Encoder
+}
+
+// SetSortKeys makes the encoder write props of objects in the order of their keys.
+func (e *LinesEncoder) SetSortKeys(sort bool) {
+	e.enc.SetSortKeys(sort)
+}
+
+// SetEscapeHTML makes the encoder escape '<', '>' and '&' in strings.
+func (e *LinesEncoder) SetEscapeHTML(escape bool) {
+	e.enc.SetEscapeHTML(escape)
+}
+
+// SetASCIIOnly makes the encoder escape non ASCII characters in strings as \uXXXX.
+func (e *LinesEncoder) SetASCIIOnly(ascii bool) {
+	e.enc.SetASCIIOnly(ascii)
+}
+
+// Encode writes the given value in a line.
+// The line never breaks inside the value as the compact form escapes line breaks in strings.
+func (e *LinesEncoder) Encode(v Value) error {
+	return e.enc.Encode(v)
+}
@@ -0,0 +1,158 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLinesDecoder(t *testing.T) {
+	src := "{\"a\": 1}\n\n  [true, \"x\"]  \r\n\"three\"\nnull"
+	expected := []struct {
+		val  string
+		line int
+	}{
+		{val: `{"a":1}`, line: 1},
+		{val: `[true,"x"]`, line: 3},
+		{val: `"three"`, line: 4},
+		{val: `null`, line: 5},
+	}
+
+	dec := NewLinesDecoder(strings.NewReader(src))
+	for _, expected := range expected {
+		var actual Value
+		if err := dec.Decode(&actual); err != nil {
+			t.Errorf("should have decoded: %s", err)
+			return
+		}
+		if s := compactString(actual); s != expected.val {
+			t.Errorf("should have decoded: %s", reportUnexpected("value", s, expected.val))
+			return
+		}
+		if dec.Line() != expected.line {
+			t.Errorf("should have reported the line: %s", reportUnexpected("line", dec.Line(), expected.line))
+			return
+		}
+	}
+
+	var actual Value
+	if err := dec.Decode(&actual); err != io.EOF {
+		t.Errorf("should have reported io.EOF: %s", reportUnexpected("error", err, io.EOF))
+		return
+	}
+}
+
+func TestLinesDecoderReportsBadLines(t *testing.T) {
+	src := "[1]\n[2, \n{\"a\": 1} {}\n[3]\n"
+	expected := []struct {
+		val string
+		err *SyntaxError
+	}{
+		{val: `[1]`},
+		{err: &SyntaxError{Line: 2, Column: 5, Offset: 8}},
+		{err: &SyntaxError{Line: 3, Column: 10, Offset: 18}},
+		{val: `[3]`},
+	}
+
+	dec := NewLinesDecoder(strings.NewReader(src))
+	for _, expected := range expected {
+		var actual Value
+		err := dec.Decode(&actual)
+		if expected.err == nil {
+			if err != nil {
+				t.Errorf("should have decoded: %s", err)
+				return
+			}
+			if s := compactString(actual); s != expected.val {
+				t.Errorf("should have decoded: %s", reportUnexpected("value", s, expected.val))
+				return
+			}
+			continue
+		}
+
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("should have returned SyntaxError: %v", err)
+			return
+		}
+		actualPos := [3]int{syntaxErr.Line, syntaxErr.Column, syntaxErr.Offset}
+		expectedPos := [3]int{expected.err.Line, expected.err.Column, expected.err.Offset}
+		if actualPos != expectedPos {
+			t.Errorf("should have reported the position in the whole source: %s", reportUnexpected("line, column and offset", actualPos, expectedPos))
+			return
+		}
+	}
+}
+
+func TestLinesDecoderSkipsBadLines(t *testing.T) {
+	src := "[1]\nbroken\n{\"a\": \n\n[2]\n[3] [4]"
+	expected := []string{`[1]`, `[2]`}
+
+	dec := NewLinesDecoder(strings.NewReader(src))
+	dec.SetSkipBadLines(true)
+
+	var actual []string
+	for {
+		var v Value
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Errorf("should have skipped bad lines: %s", err)
+			return
+		}
+		actual = append(actual, compactString(v))
+	}
+
+	if strings.Join(actual, " ") != strings.Join(expected, " ") {
+		t.Errorf("should have decoded the good lines: %s", reportUnexpected("values", actual, expected))
+		return
+	}
+	if dec.Skipped() != 3 {
+		t.Errorf("should have counted the skipped lines: %s", reportUnexpected("skipped", dec.Skipped(), 3))
+		return
+	}
+}
+
+func TestLinesEncoder(t *testing.T) {
+	vals := []Value{
+		NewObject(
+			NewProp("msg", String("line 1\nline 2")),
+			NewProp("n", Num{literal: "1"}),
+		),
+		Array{Bool(true), Null{}},
+		String("<a>"),
+	}
+	expected := "{\"msg\":\"line 1\\nline 2\",\"n\":1}\n[true,null]\n\"\\u003ca\\u003e\"\n"
+
+	var b bytes.Buffer
+	enc := NewLinesEncoder(&b)
+	enc.SetEscapeHTML(true)
+	for _, v := range vals {
+		if err := enc.Encode(v); err != nil {
+			t.Errorf("should have encoded: %s", err)
+			return
+		}
+	}
+
+	if b.String() != expected {
+		t.Errorf("should have encoded values in lines: %s", reportUnexpected("output", b.String(), expected))
+		return
+	}
+
+	dec := NewLinesDecoder(&b)
+	for _, expected := range vals {
+		var actual Value
+		if err := dec.Decode(&actual); err != nil {
+			t.Errorf("should have decoded the encoded: %s", err)
+			return
+		}
+		if err := assertValue(actual, expected); err != nil {
+			t.Errorf("should have given back equal value: %s", err)
+			return
+		}
+	}
+}
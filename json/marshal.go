@@ -0,0 +1,348 @@
+package json
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Marshaler is implemented by types which marshal themselves into JSON values.
+type Marshaler interface {
+	MarshalJSONValue() (Value, error)
+}
+
+// Marshal returns the compact JSON text of the given Go value converted by FromGo.
+func Marshal(v interface{}) ([]byte, error) {
+	val, err := FromGo(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := (Encoder{}).encode(&buf, val, 0); err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// FromGo converts the given Go value into JSON value.
+//
+// Go values are converted into JSON values as follows.
+//   - nil pointers, interfaces, maps and slices are converted into null.
+//   - Numbers including big.Int and big.Float are converted into Num.
+//   - Structs and maps are converted into Object. The props of maps are sorted by their keys
+//     and the keys should be strings, integers or encoding.TextMarshaler.
+//   - Slices and arrays are converted into Array.
+//   - Values and Marshalers are converted into what they are and what they return.
+//   - encoding.TextMarshalers such as time.Time are converted into String of the text which they return.
+//
+// Struct fields are mapped onto props in the same way as encoding/json including
+// the tag such as `json:"name,omitempty,string"`.
+// It returns an error if the value has a cycle.
+func FromGo(v interface{}) (Value, error) {
+	m := marshaler{
+		visiting: make(map[visitKey]bool),
+	}
+
+	return m.fromGo(reflect.ValueOf(v), nil)
+}
+
+type marshaler struct {
+	visiting map[visitKey]bool
+}
+
+// visitKey identifies a pointer, map or slice to detect cycles.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+var (
+	marshalerType     = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	bigIntType        = reflect.TypeOf(big.Int{})
+	bigFloatType      = reflect.TypeOf(big.Float{})
+)
+
+func (m marshaler) fromGo(rv reflect.Value, path valuePath) (Value, error) {
+	if !rv.IsValid() {
+		return Null{}, nil
+	}
+
+	if rv.Kind() == reflect.Ptr && rv.IsNil() || rv.Kind() == reflect.Interface && rv.IsNil() {
+		return Null{}, nil
+	}
+	if val, ok := rv.Interface().(Value); ok {
+		return val, nil
+	}
+	if rv.Type().Implements(marshalerType) {
+		return m.fromMarshaler(rv.Interface().(Marshaler), path)
+	}
+	if rv.CanAddr() && rv.Addr().Type().Implements(marshalerType) {
+		return m.fromMarshaler(rv.Addr().Interface().(Marshaler), path)
+	}
+
+	switch rv.Type() {
+	case bigIntType:
+		i := rv.Interface().(big.Int)
+		return Num{literal: i.String()}, nil
+	case bigFloatType:
+		f := rv.Interface().(big.Float)
+		n, err := NewNumFromBigFloat(&f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", path, err)
+		}
+		return n, nil
+	}
+	// The pointers are followed first so that the pointers to big.Int and big.Float are converted into Num.
+	if rv.Kind() != reflect.Ptr {
+		if rv.Type().Implements(textMarshalerType) {
+			return fromTextMarshaler(rv.Interface().(encoding.TextMarshaler), path)
+		}
+		if rv.CanAddr() && rv.Addr().Type().Implements(textMarshalerType) {
+			return fromTextMarshaler(rv.Addr().Interface().(encoding.TextMarshaler), path)
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		return m.visit(rv, path, func() (Value, error) {
+			return m.fromGo(rv.Elem(), path)
+		})
+	case reflect.Interface:
+		return m.fromGo(rv.Elem(), path)
+	case reflect.Bool:
+		return Bool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewNumFromInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return NewNumFromUint(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		n, err := NewNumFromFloat(rv.Float(), rv.Type().Bits())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", path, err)
+		}
+
+		return n, nil
+	case reflect.String:
+		return String(rv.String()), nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return Null{}, nil
+		}
+
+		return m.visit(rv, path, func() (Value, error) {
+			return m.fromArray(rv, path)
+		})
+	case reflect.Array:
+		return m.fromArray(rv, path)
+	case reflect.Map:
+		if rv.IsNil() {
+			return Null{}, nil
+		}
+
+		return m.visit(rv, path, func() (Value, error) {
+			return m.fromMap(rv, path)
+		})
+	case reflect.Struct:
+		return m.fromStruct(rv, path)
+	default:
+		return nil, fmt.Errorf("failed to convert %s: unsupported type: %s", path, rv.Type())
+	}
+}
+
+func (m marshaler) fromMarshaler(marshaler Marshaler, path valuePath) (Value, error) {
+	val, err := marshaler.MarshalJSONValue()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %T failed to marshal: %w", path, marshaler, err)
+	}
+	if val == nil {
+		return Null{}, nil
+	}
+
+	return val, nil
+}
+
+func fromTextMarshaler(marshaler encoding.TextMarshaler, path valuePath) (Value, error) {
+	text, err := marshaler.MarshalText()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %T failed to marshal text: %w", path, marshaler, err)
+	}
+
+	return String(text), nil
+}
+
+// visit calls the given function detecting cycles through the given pointer, map or slice.
+func (m marshaler) visit(rv reflect.Value, path valuePath, fn func() (Value, error)) (Value, error) {
+	key := visitKey{
+		ptr: rv.Pointer(),
+		typ: rv.Type(),
+	}
+	if rv.Kind() == reflect.Slice {
+		key.len = rv.Len()
+	}
+
+	if m.visiting[key] {
+		return nil, fmt.Errorf("failed to convert %s: cycle is detected through %s", path, rv.Type())
+	}
+	m.visiting[key] = true
+	defer delete(m.visiting, key)
+
+	return fn()
+}
+
+func (m marshaler) fromArray(rv reflect.Value, path valuePath) (Array, error) {
+	arr := make(Array, rv.Len())
+	for i := range arr {
+		elem, err := m.fromGo(rv.Index(i), path.appended(indexElem(i)))
+		if err != nil {
+			return nil, err
+		}
+		arr[i] = elem
+	}
+
+	return arr, nil
+}
+
+func (m marshaler) fromMap(rv reflect.Value, path valuePath) (Object, error) {
+	props := make([]Prop, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key, err := mapKeyString(iter.Key())
+		if err != nil {
+			return Object{}, fmt.Errorf("failed to convert %s: %w", path, err)
+		}
+
+		val, err := m.fromGo(iter.Value(), path.appended(keyElem(key)))
+		if err != nil {
+			return Object{}, err
+		}
+
+		props = append(props, Prop{
+			key: String(key),
+			val: val,
+		})
+	}
+
+	sort.Slice(props, func(i, j int) bool {
+		return props[i].key < props[j].key
+	})
+
+	return NewObject(props...), nil
+}
+
+func mapKeyString(rv reflect.Value) (string, error) {
+	if rv.Type().Implements(textMarshalerType) {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return "", nil
+		}
+
+		text, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal key: %w", err)
+		}
+
+		return string(text), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	default:
+		return "", fmt.Errorf("unsupported type of map key: %s", rv.Type())
+	}
+}
+
+func (m marshaler) fromStruct(rv reflect.Value, path valuePath) (Object, error) {
+	fields := cachedStructFields(rv.Type())
+	obj := NewObject()
+	for _, f := range fields.list {
+		frv, ok := lookupFieldByIndex(rv, f.index)
+		if !ok {
+			continue
+		}
+		if f.omitEmpty && isEmptyValue(frv) {
+			continue
+		}
+
+		propPath := path.appended(keyElem(f.name))
+		val, err := m.fromGo(frv, propPath)
+		if err != nil {
+			return Object{}, err
+		}
+		if f.asString {
+			val, err = quoteAsString(val)
+			if err != nil {
+				return Object{}, fmt.Errorf("failed to convert %s: %w", propPath, err)
+			}
+		}
+
+		obj.append(NewProp(f.name, val))
+	}
+
+	return obj, nil
+}
+
+// lookupFieldByIndex returns the field
+// unless it is in an embedded struct through nil pointer.
+func lookupFieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+
+	return rv, true
+}
+
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// quoteAsString wraps the given scalar in string for fields with the string option.
+func quoteAsString(val Value) (Value, error) {
+	switch val := val.(type) {
+	case Null:
+		return val, nil
+	case Bool:
+		if val {
+			return String(literalTrue), nil
+		}
+		return String(literalFalse), nil
+	case Num:
+		return String(val.literal), nil
+	case String:
+		return String(quoteString(string(val), false, false)), nil
+	default:
+		return nil, fmt.Errorf("%s cannot be wrapped in string", val.Kind())
+	}
+}
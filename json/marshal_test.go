@@ -0,0 +1,243 @@
+package json
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testProduct struct {
+	testBase
+	Title    string          `json:"title"`
+	Price    *big.Float      `json:"price"`
+	Stock    uint64          `json:"stock,string"`
+	OnSale   bool            `json:"on_sale,omitempty"`
+	Labels   []string        `json:"labels,omitempty"`
+	Ratings  map[testKey]int `json:"ratings"`
+	Related  *testProduct    `json:"related,omitempty"`
+	Status   testStatus      `json:"status"`
+	Raw      Value           `json:"raw"`
+	Attrs    map[int]string  `json:"attrs"`
+	Ignored  string          `json:"-"`
+	Untagged string
+}
+
+type testEmbedding struct {
+	*testMeta
+	Title string `json:"title"`
+}
+
+type testStatus int
+
+func (s testStatus) MarshalJSONValue() (Value, error) {
+	if s == 0 {
+		return String("draft"), nil
+	}
+
+	return String("published"), nil
+}
+
+func (l testLevel) MarshalJSONValue() (Value, error) {
+	switch l {
+	case 1:
+		return String("low"), nil
+	case 2:
+		return String("high"), nil
+	default:
+		return Null{}, nil
+	}
+}
+
+func (k testKey) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(string(k))), nil
+}
+
+func TestMarshal(t *testing.T) {
+	price, _ := new(big.Float).SetString("1234567890.25")
+	product := testProduct{
+		testBase: testBase{ID: 1, Name: "base"},
+		Title:    "book",
+		Price:    price,
+		Stock:    18446744073709551615,
+		Ratings:  map[testKey]int{"B": 2, "A": 1},
+		Related:  &testProduct{Title: "pen", Status: 1},
+		Raw:      Array{Null{}},
+		Attrs:    map[int]string{10: "ten", 2: "two"},
+		Ignored:  "ignored",
+		Untagged: "untagged",
+	}
+	expected := `{"id":1,"base_name":"base","title":"book","price":1234567890.25,"stock":"18446744073709551615",` +
+		`"ratings":{"a":1,"b":2},` +
+		`"related":{"id":0,"base_name":"","title":"pen","price":null,"stock":"0","ratings":null,"status":"published","raw":null,"attrs":null,"Untagged":""},` +
+		`"status":"draft","raw":[null],"attrs":{"10":"ten","2":"two"},"Untagged":"untagged"}`
+
+	actual, err := Marshal(product)
+	if err != nil {
+		t.Errorf("should have marshaled: %s", err)
+		return
+	}
+	if string(actual) != expected {
+		t.Errorf("should have marshaled: %s", reportUnexpected("output", string(actual), expected))
+		return
+	}
+}
+
+// testStamp implements encoding.TextMarshaler with the pointer receiver.
+type testStamp struct {
+	unix int64
+}
+
+func (s *testStamp) MarshalText() ([]byte, error) {
+	if s.unix < 0 {
+		return nil, errors.New("negative stamp")
+	}
+
+	return []byte("stamp-" + strconv.FormatInt(s.unix, 10)), nil
+}
+
+func TestFromGo(t *testing.T) {
+	tests := map[string]struct {
+		v        interface{}
+		expected Value
+	}{
+		"nil": {
+			v:        nil,
+			expected: Null{},
+		},
+		"float": {
+			v:        1e-7,
+			expected: Num{literal: "1e-7"},
+		},
+		"big int": {
+			v:        new(big.Int).Lsh(big.NewInt(1), 100),
+			expected: Num{literal: "1267650600228229401496703205376"},
+		},
+		"text marshaler": {
+			v:        time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			expected: String("2026-01-02T03:04:05Z"),
+		},
+		"pointer to text marshaler": {
+			v:        &testStamp{unix: 1},
+			expected: String("stamp-1"),
+		},
+		"text marshaler in struct": {
+			v: struct {
+				At   time.Time  `json:"at"`
+				IP   net.IP     `json:"ip"`
+				Next *time.Time `json:"next"`
+			}{
+				At: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+				IP: net.IPv4(127, 0, 0, 1),
+			},
+			expected: NewObject(
+				Prop{key: "at", val: String("2026-01-02T00:00:00Z")},
+				Prop{key: "ip", val: String("127.0.0.1")},
+				Prop{key: "next", val: Null{}},
+			),
+		},
+		"addressable text marshaler": {
+			v:        []testStamp{{unix: 2}},
+			expected: Array{String("stamp-2")},
+		},
+		"nil slice": {
+			v:        []int(nil),
+			expected: Null{},
+		},
+		"interface slice": {
+			v:        []interface{}{1, "two", true, nil, map[string]interface{}{"a": 1.5}},
+			expected: Array{Num{literal: "1"}, String("two"), Bool(true), Null{}, NewObject(Prop{key: "a", val: Num{literal: "1.5"}})},
+		},
+		"embedded pointer": {
+			v: testEmbedding{testMeta: &testMeta{Note: "note"}, Title: "title"},
+			expected: NewObject(
+				Prop{key: "note", val: String("note")},
+				Prop{key: "title", val: String("title")},
+			),
+		},
+		"embedded nil pointer": {
+			v: testEmbedding{Title: "title"},
+			expected: NewObject(
+				Prop{key: "title", val: String("title")},
+			),
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			actual, err := FromGo(test.v)
+			if err != nil {
+				t.Errorf("should have converted: %s", err)
+				return
+			}
+			if err := assertValue(actual, test.expected); err != nil {
+				t.Errorf("should have converted: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestFromGoFails(t *testing.T) {
+	cyclic := &testProduct{}
+	cyclic.Related = cyclic
+
+	cyclicMap := map[string]interface{}{}
+	cyclicMap["self"] = cyclicMap
+
+	tests := map[string]interface{}{
+		"cycle through pointer": cyclic,
+		"cycle through map":     cyclicMap,
+		"NaN":                   math.NaN(),
+		"infinity":              []float64{math.Inf(1)},
+		"channel":               make(chan int),
+		"unsupported map key":   map[float64]int{1: 1},
+		"text marshaler error":  &testStamp{unix: -1},
+	}
+
+	for n, v := range tests {
+		t.Run(n, func(t *testing.T) {
+			if _, err := FromGo(v); err == nil {
+				t.Errorf("should have failed to convert: %v", v)
+				return
+			}
+		})
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	expected := testUser{
+		testBase: testBase{ID: 18446744073709551615, Name: "base"},
+		Name:     "alice",
+		Age:      20,
+		Score:    98.5,
+		Admin:    true,
+		Tags:     []string{"a", "b"},
+		Scores:   map[string]int{"math": 100},
+		Friend:   &testUser{Name: "bob", Raw: Null{}},
+		Raw:      NewObject(Prop{key: "a", val: Array{}}),
+		Point:    [2]int{3, 4},
+		Level:    2,
+	}
+
+	marshaled, err := Marshal(expected)
+	if err != nil {
+		t.Errorf("should have marshaled: %s", err)
+		return
+	}
+
+	var actual testUser
+	if err := Unmarshal(marshaled, &actual); err != nil {
+		t.Errorf("should have unmarshaled: %s", err)
+		return
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("should have given back equal value: %s", reportUnexpected("value", actual, expected))
+		return
+	}
+}
@@ -0,0 +1,118 @@
+package json
+
+import (
+	"fmt"
+)
+
+// MergePatch applies the patch to the target following JSON Merge Patch defined in RFC 7396.
+// Null in the patch removes the prop, objects are merged recursively, and the others replace the target.
+//
+// It does not modify the target but returns the patched copy of it,
+// which keeps the order of the props in the target and appends the new props in the order in the patch.
+func MergePatch(target, patch Value) Value {
+	patchObj, ok := patch.(Object)
+	if !ok {
+		return patch
+	}
+
+	targetObj, _ := target.(Object)
+	merged := targetObj.Clone()
+
+	patchObj.Range(func(key string, val Value) bool {
+		if _, ok := val.(Null); ok {
+			merged.Delete(key)
+			return true
+		}
+
+		old, _ := merged.Get(key)
+		merged.Set(key, MergePatch(old, val))
+
+		return true
+	})
+
+	return merged
+}
+
+// CreateMergePatch returns the merge patch which transforms from into to.
+// It returns an error if to has null in objects, which merge patches cannot represent
+// as null in them means removal.
+func CreateMergePatch(from, to Value) (Value, error) {
+	patch, err := createMergePatch(from, to, Pointer{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge patch: %w", err)
+	}
+
+	return patch, nil
+}
+
+func createMergePatch(from, to Value, at Pointer) (Value, error) {
+	toObj, ok := to.(Object)
+	if !ok {
+		return to, nil
+	}
+	fromObj, ok := from.(Object)
+	if !ok {
+		if err := validateMergePatchValue(toObj, at); err != nil {
+			return nil, err
+		}
+		return toObj, nil
+	}
+
+	patch := NewObject()
+	// Only the last one of the props with the same key is effective.
+	for _, prop := range toObj.effectiveProps() {
+		key := string(prop.key)
+		fromVal, ok := fromObj.Get(key)
+		if !ok {
+			if err := validateMergePatchValue(prop.val, at.appended(key)); err != nil {
+				return nil, err
+			}
+			patch.Set(key, prop.val)
+			continue
+		}
+		if equalValues(fromVal, prop.val) {
+			continue
+		}
+		if _, ok := prop.val.(Null); ok {
+			return nil, fmt.Errorf("null at %q cannot be represented", at.appended(key))
+		}
+
+		val, err := createMergePatch(fromVal, prop.val, at.appended(key))
+		if err != nil {
+			return nil, err
+		}
+		patch.Set(key, val)
+	}
+
+	for _, prop := range fromObj.effectiveProps() {
+		key := string(prop.key)
+		if toObj.Has(key) {
+			continue
+		}
+
+		patch.Set(key, Null{})
+	}
+
+	return patch, nil
+}
+
+// validateMergePatchValue validates that the value is applied as it is,
+// which means that the value does not have null in objects.
+func validateMergePatchValue(val Value, at Pointer) error {
+	switch val := val.(type) {
+	case Null:
+		if len(at) != 0 {
+			return fmt.Errorf("null at %q cannot be represented", at)
+		}
+		return nil
+	case Object:
+		for _, prop := range val.Props() {
+			if err := validateMergePatchValue(prop.val, at.appended(string(prop.key))); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
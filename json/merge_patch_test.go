@@ -0,0 +1,219 @@
+package json
+
+import (
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := map[string]struct {
+		target, patch string
+		expected      string
+	}{
+		"replace prop": {
+			target:   `{"a": "b"}`,
+			patch:    `{"a": "c"}`,
+			expected: `{"a":"c"}`,
+		},
+		"add prop": {
+			target:   `{"a": "b"}`,
+			patch:    `{"b": "c"}`,
+			expected: `{"a":"b","b":"c"}`,
+		},
+		"remove prop": {
+			target:   `{"a": "b"}`,
+			patch:    `{"a": null}`,
+			expected: `{}`,
+		},
+		"remove one of props": {
+			target:   `{"a": "b", "b": "c"}`,
+			patch:    `{"a": null}`,
+			expected: `{"b":"c"}`,
+		},
+		"replace array": {
+			target:   `{"a": ["b"]}`,
+			patch:    `{"a": "c"}`,
+			expected: `{"a":"c"}`,
+		},
+		"replace with array": {
+			target:   `{"a": "c"}`,
+			patch:    `{"a": ["b"]}`,
+			expected: `{"a":["b"]}`,
+		},
+		"merge nested objects": {
+			target:   `{"a": {"b": "c"}}`,
+			patch:    `{"a": {"b": "d", "c": null}}`,
+			expected: `{"a":{"b":"d"}}`,
+		},
+		"replace array of objects": {
+			target:   `{"a": [{"b": "c"}]}`,
+			patch:    `{"a": [1]}`,
+			expected: `{"a":[1]}`,
+		},
+		"replace non object": {
+			target:   `["a", "b"]`,
+			patch:    `["c", "d"]`,
+			expected: `["c","d"]`,
+		},
+		"replace object with array": {
+			target:   `{"a": "b"}`,
+			patch:    `["c"]`,
+			expected: `["c"]`,
+		},
+		"replace object with null": {
+			target:   `{"a": "foo"}`,
+			patch:    `null`,
+			expected: `null`,
+		},
+		"merge into non object": {
+			target:   `["c"]`,
+			patch:    `{"a": "b", "c": null}`,
+			expected: `{"a":"b"}`,
+		},
+		"add nested object without null": {
+			target:   `{}`,
+			patch:    `{"a": {"bb": {"ccc": null}}}`,
+			expected: `{"a":{"bb":{}}}`,
+		},
+		"keep order": {
+			target:   `{"c": 1, "a": 2, "b": 3}`,
+			patch:    `{"d": 4, "a": 5, "c": null}`,
+			expected: `{"a":5,"b":3,"d":4}`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			target, err := ParseString(test.target)
+			if err != nil {
+				t.Errorf("should have parsed the target: %s", err)
+				return
+			}
+			patch, err := ParseString(test.patch)
+			if err != nil {
+				t.Errorf("should have parsed the patch: %s", err)
+				return
+			}
+			original, _ := ParseString(test.target)
+
+			actual, err := Marshal(MergePatch(target, patch))
+			if err != nil {
+				t.Errorf("should have marshaled: %s", err)
+				return
+			}
+			if string(actual) != test.expected {
+				t.Errorf("should have merged: %s", reportUnexpected("value", string(actual), test.expected))
+				return
+			}
+			if err := assertValue(target, original); err != nil {
+				t.Errorf("should not have modified the target: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	tests := map[string]struct {
+		from, to string
+		expected string
+	}{
+		"equal": {
+			from:     `{"a": 1, "b": [1]}`,
+			to:       `{"b": [1], "a": 1.0}`,
+			expected: `{}`,
+		},
+		"props": {
+			from:     `{"a": 1, "b": {"c": 2, "d": 3}, "e": 4}`,
+			to:       `{"a": 1, "b": {"c": 5, "d": 3}, "f": 6}`,
+			expected: `{"b":{"c":5},"f":6,"e":null}`,
+		},
+		"array": {
+			from:     `{"a": [1, 2]}`,
+			to:       `{"a": [1, null]}`,
+			expected: `{"a":[1,null]}`,
+		},
+		"object replacing scalar": {
+			from:     `{"a": 1}`,
+			to:       `{"a": {"b": 2}}`,
+			expected: `{"a":{"b":2}}`,
+		},
+		"scalar": {
+			from:     `{"a": 1}`,
+			to:       `null`,
+			expected: `null`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			from, err := ParseString(test.from)
+			if err != nil {
+				t.Errorf("should have parsed from: %s", err)
+				return
+			}
+			to, err := ParseString(test.to)
+			if err != nil {
+				t.Errorf("should have parsed to: %s", err)
+				return
+			}
+
+			patch, err := CreateMergePatch(from, to)
+			if err != nil {
+				t.Errorf("should have created: %s", err)
+				return
+			}
+			actual, err := Marshal(patch)
+			if err != nil {
+				t.Errorf("should have marshaled: %s", err)
+				return
+			}
+			if string(actual) != test.expected {
+				t.Errorf("should have created: %s", reportUnexpected("patch", string(actual), test.expected))
+				return
+			}
+			if merged := MergePatch(from, patch); !equalValues(merged, to) {
+				t.Errorf("should have transformed from into to: %s", reportUnexpected("value", merged, to))
+				return
+			}
+		})
+	}
+}
+
+func TestCreateMergePatchFails(t *testing.T) {
+	tests := map[string]struct {
+		from, to string
+	}{
+		"null replacing value": {
+			from: `{"a": 1}`,
+			to:   `{"a": null}`,
+		},
+		"new null": {
+			from: `{}`,
+			to:   `{"a": {"b": null}}`,
+		},
+		"null in object replacing scalar": {
+			from: `1`,
+			to:   `{"a": null}`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			from, err := ParseString(test.from)
+			if err != nil {
+				t.Errorf("should have parsed from: %s", err)
+				return
+			}
+			to, err := ParseString(test.to)
+			if err != nil {
+				t.Errorf("should have parsed to: %s", err)
+				return
+			}
+
+			if _, err := CreateMergePatch(from, to); err == nil {
+				t.Errorf("should have failed to create")
+				return
+			}
+		})
+	}
+}
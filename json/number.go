@@ -0,0 +1,406 @@
+package json
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Num is a JSON number which keeps its original literal
+// so that it never loses precision until it is converted explicitly.
+type Num struct {
+	literal string
+}
+
+// NewNum returns the Num of the given literal
+// if it follows the grammar of number in RFC 8259.
+func NewNum(lit string) (Num, error) {
+	if err := validateNumLiteral(lit); err != nil {
+		return Num{}, fmt.Errorf("invalid number format: %w", err)
+	}
+
+	return Num{
+		literal: lit,
+	}, nil
+}
+
+func (Num) Kind() Kind { return KindNum }
+
+func (Num) value() {}
+
+func (n Num) String() string {
+	return n.literal
+}
+
+func (n Num) Int64() (int64, error) {
+	if parsed, err := strconv.ParseInt(n.literal, 10, 64); err == nil {
+		return parsed, nil
+	}
+
+	i, err := n.BigInt()
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() {
+		return 0, fmt.Errorf("%s overflows int64", n.literal)
+	}
+
+	return i.Int64(), nil
+}
+
+func (n Num) Float64() (float64, error) {
+	parsed, err := strconv.ParseFloat(n.literal, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert %s into float64: %w", n.literal, err)
+	}
+
+	return parsed, nil
+}
+
+// maxBigIntExp limits the exponent which BigInt expands
+// so that a short literal such as 1e1000000000 cannot exhaust memory.
+const maxBigIntExp = 1 << 16
+
+func (n Num) BigInt() (*big.Int, error) {
+	parts, err := splitNumLiteral(n.literal)
+	if err != nil {
+		return nil, err
+	}
+
+	digits := strings.TrimLeft(parts.integer+parts.fraction, "0")
+	if digits == "" {
+		return new(big.Int), nil
+	}
+
+	scale := parts.exp - len(parts.fraction)
+	if scale < 0 {
+		trimmed := strings.TrimRight(digits, "0")
+		if len(digits)-len(trimmed) < -scale {
+			return nil, fmt.Errorf("%s is not an integer", n.literal)
+		}
+		digits, scale = digits[:len(digits)+scale], 0
+	}
+	if scale > maxBigIntExp {
+		return nil, fmt.Errorf("exponent of %s is too large", n.literal)
+	}
+
+	i, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert %s into big.Int", n.literal)
+	}
+	if scale > 0 {
+		i.Mul(i, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil))
+	}
+	if parts.negative {
+		i.Neg(i)
+	}
+
+	return i, nil
+}
+
+// isInt reports whether the number is an integer such as 1.0 and 1.5e1 without expanding its exponent.
+func (n Num) isInt() bool {
+	parts, err := splitNumLiteral(n.literal)
+	if err != nil {
+		return false
+	}
+
+	digits := strings.TrimLeft(parts.integer+parts.fraction, "0")
+	scale := parts.exp - len(parts.fraction)
+	if digits == "" || scale >= 0 {
+		return true
+	}
+
+	return len(digits)-len(strings.TrimRight(digits, "0")) >= -scale
+}
+
+func (n Num) BigFloat() (*big.Float, error) {
+	return n.bigFloat(n.prec())
+}
+
+// prec returns the precision which is enough to tell the number from the other numbers with as many digits.
+func (n Num) prec() uint {
+	// 4 bits for each digit is enough to hold a decimal digit.
+	prec := uint(len(n.literal)) * 4
+	if prec < 64 {
+		prec = 64
+	}
+
+	return prec
+}
+
+func (n Num) bigFloat(prec uint) (*big.Float, error) {
+	f, _, err := big.ParseFloat(n.literal, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s into big.Float: %w", n.literal, err)
+	}
+
+	return f, nil
+}
+
+// cmp compares n with m numerically so that 1 and 1.0 are equal for example.
+func (n Num) cmp(m Num) int {
+	if n.literal == m.literal {
+		return 0
+	}
+
+	prec := n.prec()
+	if p := m.prec(); p > prec {
+		prec = p
+	}
+
+	x, err := n.bigFloat(prec)
+	if err != nil {
+		return strings.Compare(n.literal, m.literal)
+	}
+	y, err := m.bigFloat(prec)
+	if err != nil {
+		return strings.Compare(n.literal, m.literal)
+	}
+
+	return x.Cmp(y)
+}
+
+// bigRat returns the exact value of the number.
+func (n Num) bigRat() (*big.Rat, error) {
+	parts, err := splitNumLiteral(n.literal)
+	if err != nil {
+		return nil, err
+	}
+	if parts.exp > maxBigIntExp || parts.exp < -maxBigIntExp {
+		return nil, fmt.Errorf("exponent of %s is too large", n.literal)
+	}
+
+	r, ok := new(big.Rat).SetString(n.literal)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert %s into big.Rat", n.literal)
+	}
+
+	return r, nil
+}
+
+type numLiteralParts struct {
+	negative bool
+	integer  string
+	fraction string
+	exp      int
+}
+
+func splitNumLiteral(lit string) (numLiteralParts, error) {
+	if err := validateNumLiteral(lit); err != nil {
+		return numLiteralParts{}, err
+	}
+
+	var parts numLiteralParts
+	if lit[0] == '-' {
+		parts.negative = true
+		lit = lit[1:]
+	}
+
+	mantissa := lit
+	if i := strings.IndexAny(lit, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(lit[i+1:])
+		if err != nil {
+			return numLiteralParts{}, fmt.Errorf("exponent of %s is out of range", lit)
+		}
+		mantissa, parts.exp = lit[:i], exp
+	}
+
+	parts.integer = mantissa
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		parts.integer, parts.fraction = mantissa[:i], mantissa[i+1:]
+	}
+
+	return parts, nil
+}
+
+// validateNumLiteral validates the given literal against the grammar of number in RFC 8259.
+//
+//	number = [ minus ] int [ frac ] [ exp ]
+func validateNumLiteral(lit string) error {
+	i := 0
+	if i < len(lit) && lit[i] == '-' {
+		i++
+	}
+
+	switch {
+	case i >= len(lit):
+		return fmt.Errorf("%q should have digits", lit)
+	case lit[i] == '0':
+		i++
+		if i < len(lit) && isNum(rune(lit[i])) {
+			return fmt.Errorf("%q should not have leading zeros", lit)
+		}
+	case isNum(rune(lit[i])):
+		i = skipDigits(lit, i)
+	default:
+		return fmt.Errorf("%q should start with digit or '-'", lit)
+	}
+
+	if i < len(lit) && lit[i] == '.' {
+		i++
+		j := skipDigits(lit, i)
+		if j == i {
+			return fmt.Errorf("%q should have digits after '.'", lit)
+		}
+		i = j
+	}
+
+	if i < len(lit) && (lit[i] == 'e' || lit[i] == 'E') {
+		i++
+		if i < len(lit) && (lit[i] == '+' || lit[i] == '-') {
+			i++
+		}
+		j := skipDigits(lit, i)
+		if j == i {
+			return fmt.Errorf("%q should have digits in exponent", lit)
+		}
+		i = j
+	}
+
+	if i != len(lit) {
+		return fmt.Errorf("%q has unexpected character '%c'", lit, lit[i])
+	}
+
+	return nil
+}
+
+// normalizeJSON5NumLiteral converts the given literal of number of JSON5 into the equivalent literal of JSON
+// such as 31 for 0x1F, 0.5 for .5 and 1 for +1.
+// It keeps Infinity, -Infinity and NaN as they are, which JSON cannot represent.
+func normalizeJSON5NumLiteral(lit string) (string, error) {
+	sign, unsigned := "", lit
+	if lit != "" && (lit[0] == '+' || lit[0] == '-') {
+		sign, unsigned = lit[:1], lit[1:]
+	}
+	if sign == "+" {
+		sign = ""
+	}
+
+	switch {
+	case unsigned == literalInfinity:
+		return sign + unsigned, nil
+	case unsigned == literalNaN:
+		// NaN has no sign.
+		return unsigned, nil
+	case strings.HasPrefix(unsigned, "0x") || strings.HasPrefix(unsigned, "0X"):
+		i, ok := new(big.Int).SetString(unsigned[2:], 16)
+		if !ok || strings.ContainsAny(unsigned[2:], "+-") {
+			return "", fmt.Errorf("%q should have hex digits after '0x'", lit)
+		}
+		if i.Sign() == 0 {
+			sign = ""
+		}
+		return sign + i.String(), nil
+	}
+
+	mantissa, exp := unsigned, ""
+	if i := strings.IndexAny(unsigned, "eE"); i >= 0 {
+		mantissa, exp = unsigned[:i], unsigned[i:]
+	}
+	if mantissa == "." {
+		return "", fmt.Errorf("%q should have digits", lit)
+	}
+	if strings.HasPrefix(mantissa, ".") {
+		mantissa = "0" + mantissa
+	}
+	mantissa = strings.TrimSuffix(mantissa, ".")
+
+	normalized := sign + mantissa + exp
+	if err := validateNumLiteral(normalized); err != nil {
+		return "", fmt.Errorf("%q is invalid: %w", lit, err)
+	}
+
+	return normalized, nil
+}
+
+func isNonFiniteNumLiteral(lit string) bool {
+	return lit == literalInfinity || lit == "-"+literalInfinity || lit == literalNaN
+}
+
+func skipDigits(s string, i int) int {
+	for i < len(s) && isNum(rune(s[i])) {
+		i++
+	}
+
+	return i
+}
+
+func NewNumFromInt(i int64) Num {
+	return Num{
+		literal: strconv.FormatInt(i, 10),
+	}
+}
+
+func NewNumFromUint(i uint64) Num {
+	return Num{
+		literal: strconv.FormatUint(i, 10),
+	}
+}
+
+// NewNumFromFloat returns the Num of the given float
+// formatted in the same way as ECMAScript formats numbers.
+// It returns an error for NaN and infinities as JSON cannot represent them.
+func NewNumFromFloat(f float64, bits int) (Num, error) {
+	lit, err := formatFloat(f, bits)
+	if err != nil {
+		return Num{}, err
+	}
+
+	return Num{
+		literal: lit,
+	}, nil
+}
+
+func formatFloat(f float64, bits int) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("%v cannot be represented in JSON", f)
+	}
+	if f == 0 {
+		// -0 is formatted as 0 in the same way as ECMAScript.
+		return "0", nil
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+
+	formatted := strconv.FormatFloat(f, format, -1, bits)
+	if format == 'e' {
+		// 1e-07 is formatted as 1e-7 in the same way as ECMAScript.
+		n := len(formatted)
+		if n >= 4 && formatted[n-4] == 'e' && formatted[n-3] == '-' && formatted[n-2] == '0' {
+			formatted = formatted[:n-2] + formatted[n-1:]
+		}
+	}
+
+	return formatted, nil
+}
+
+var (
+	minFixedBigFloat = big.NewFloat(1e-6)
+	maxFixedBigFloat = big.NewFloat(1e21)
+)
+
+// NewNumFromBigFloat returns the Num of the given float
+// formatted in the same way as NewNumFromFloat except that it keeps all the digits.
+func NewNumFromBigFloat(f *big.Float) (Num, error) {
+	if f.IsInf() {
+		return Num{}, fmt.Errorf("%v cannot be represented in JSON", f)
+	}
+	if f.Sign() == 0 {
+		return Num{literal: "0"}, nil
+	}
+
+	format := byte('f')
+	if abs := new(big.Float).Abs(f); abs.Cmp(minFixedBigFloat) < 0 || abs.Cmp(maxFixedBigFloat) >= 0 {
+		format = 'e'
+	}
+
+	return Num{
+		literal: f.Text(format, -1),
+	}, nil
+}
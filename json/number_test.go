@@ -0,0 +1,153 @@
+package json
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestNumInt64(t *testing.T) {
+	tests := map[string]struct {
+		num      Num
+		expected int64
+	}{
+		"int": {
+			num:      Num{literal: "1"},
+			expected: 1,
+		},
+		"negative": {
+			num:      Num{literal: "-42"},
+			expected: -42,
+		},
+		"over int32": {
+			num:      Num{literal: "9007199254740993"},
+			expected: 9007199254740993,
+		},
+		"exponent": {
+			num:      Num{literal: "1.5e3"},
+			expected: 1500,
+		},
+		"zero fraction": {
+			num:      Num{literal: "10.00"},
+			expected: 10,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			actual, err := test.num.Int64()
+			if err != nil {
+				t.Errorf("should have converted: %s", err)
+				return
+			}
+			if actual != test.expected {
+				t.Errorf("should have converted: %s", reportUnexpected("value", actual, test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestNumInt64Fails(t *testing.T) {
+	tests := map[string]Num{
+		"fraction":       {literal: "1.5"},
+		"over int64":     {literal: "9223372036854775808"},
+		"huge exponent":  {literal: "1e100000"},
+		"small exponent": {literal: "1e-1"},
+	}
+
+	for n, num := range tests {
+		t.Run(n, func(t *testing.T) {
+			if _, err := num.Int64(); err == nil {
+				t.Errorf("should have failed to convert: %s", num)
+				return
+			}
+		})
+	}
+}
+
+func TestNumFloat64(t *testing.T) {
+	tests := map[string]struct {
+		num      Num
+		expected float64
+	}{
+		"int": {
+			num:      Num{literal: "1"},
+			expected: 1,
+		},
+		"fraction": {
+			num:      Num{literal: "-0.25"},
+			expected: -0.25,
+		},
+		"exponent": {
+			num:      Num{literal: "2.5E-3"},
+			expected: 0.0025,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			actual, err := test.num.Float64()
+			if err != nil {
+				t.Errorf("should have converted: %s", err)
+				return
+			}
+			if actual != test.expected {
+				t.Errorf("should have converted: %s", reportUnexpected("value", actual, test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestNumBigInt(t *testing.T) {
+	tests := map[string]struct {
+		num      Num
+		expected string
+	}{
+		"over int64": {
+			num:      Num{literal: "123456789012345678901234567890"},
+			expected: "123456789012345678901234567890",
+		},
+		"negative": {
+			num:      Num{literal: "-123456789012345678901234567890"},
+			expected: "-123456789012345678901234567890",
+		},
+		"exponent": {
+			num:      Num{literal: "12.34e20"},
+			expected: "1234000000000000000000",
+		},
+		"zero": {
+			num:      Num{literal: "-0.0e10"},
+			expected: "0",
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			actual, err := test.num.BigInt()
+			if err != nil {
+				t.Errorf("should have converted: %s", err)
+				return
+			}
+			if actual.String() != test.expected {
+				t.Errorf("should have converted: %s", reportUnexpected("value", actual, test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestNumBigFloat(t *testing.T) {
+	num := Num{literal: "12345678901234567890.123456789"}
+	expected, _, _ := big.ParseFloat("12345678901234567890.123456789", 10, 256, big.ToNearestEven)
+
+	actual, err := num.BigFloat()
+	if err != nil {
+		t.Errorf("should have converted: %s", err)
+		return
+	}
+	if actual.Text('f', 9) != expected.Text('f', 9) {
+		t.Errorf("should have converted: %s", reportUnexpected("value", actual.Text('f', 9), expected.Text('f', 9)))
+		return
+	}
+}
@@ -0,0 +1,186 @@
+package json
+
+import "github.com/tomocy/go-cookbook/internal/orderedmap"
+
+// NewObject returns the object which has the given props in the order.
+// The props with the same key are all kept, where the last one is effective in the same way as the parsed objects.
+func NewObject(props ...Prop) Object {
+	obj := Object{
+		m: &orderedmap.Map{},
+	}
+	for _, prop := range props {
+		obj.append(prop)
+	}
+
+	return obj
+}
+
+// ObjectFromMap returns the object which has the props converted from the given map by FromGo
+// in the order of their keys.
+func ObjectFromMap(m map[string]interface{}) (Object, error) {
+	val, err := FromGo(m)
+	if err != nil {
+		return Object{}, err
+	}
+	if _, ok := val.(Null); ok {
+		return NewObject(), nil
+	}
+
+	return val.(Object), nil
+}
+
+// Object is a JSON object, which is a map keeping its props in the order they are added.
+// It takes constant time to get, set and delete the props by their keys.
+//
+// Object refers to its props in the same way as Go maps do, so its copies share the props with it.
+// Use Clone to have an object which can be modified independently.
+// The zero value is an empty object, to which props can be set.
+type Object struct {
+	m *orderedmap.Map
+}
+
+func (Object) Kind() Kind { return KindObject }
+
+func (Object) value() {}
+
+// Len returns the number of the props including the ones with the same key.
+func (o Object) Len() int {
+	if o.m == nil {
+		return 0
+	}
+
+	return o.m.Len()
+}
+
+// Get returns the value of the prop with the given key.
+// The last one is returned if the object has several props with the key.
+func (o Object) Get(key string) (Value, bool) {
+	if o.m == nil {
+		return nil, false
+	}
+
+	i := o.m.Lookup(key)
+	if i < 0 {
+		return nil, false
+	}
+
+	return propValue(o.m.At(i)), true
+}
+
+// Has reports whether the object has the prop with the given key.
+func (o Object) Has(key string) bool {
+	_, ok := o.Get(key)
+	return ok
+}
+
+// Set replaces the value of the prop with the given key keeping its position,
+// or adds the prop as the last one if the object does not have it.
+// The last one is replaced if the object has several props with the key.
+func (o *Object) Set(key string, val Value) {
+	if o.m == nil {
+		o.m = &orderedmap.Map{}
+	}
+
+	if i := o.m.Lookup(key); i >= 0 {
+		o.m.SetAt(i, val)
+		return
+	}
+
+	o.append(NewProp(key, val))
+}
+
+// Delete deletes all the props with the given key, and reports whether the object had them.
+func (o *Object) Delete(key string) bool {
+	if o.m == nil {
+		return false
+	}
+
+	return o.m.Delete(key)
+}
+
+// Props returns the props in the order, where the props with the same key are all included.
+func (o Object) Props() []Prop {
+	props := make([]Prop, 0, o.Len())
+	o.Range(func(key string, val Value) bool {
+		props = append(props, NewProp(key, val))
+		return true
+	})
+
+	return props
+}
+
+// Range calls fn for each prop in the order until fn returns false.
+// The props with the same key are all visited.
+func (o Object) Range(fn func(key string, val Value) bool) {
+	if o.m == nil {
+		return
+	}
+
+	o.m.Range(func(_ int, key string, val interface{}) bool {
+		return fn(key, propValue(val))
+	})
+}
+
+// ToMap converts the object into the map whose values are converted as follows.
+//
+//	Null is converted into nil.
+//	Bool is converted into bool.
+//	Num is kept as it is so as not to lose its precision.
+//	String is converted into string.
+//	Array is converted into []interface{}.
+//	Object is converted into map[string]interface{}.
+//
+// The last prop is effective if the object has several props with the same key.
+func (o Object) ToMap() map[string]interface{} {
+	return toGo(o).(map[string]interface{})
+}
+
+// Equal reports whether the object has the same props as the other regardless of their order,
+// where numbers are compared numerically and only the last props are compared among the ones with the same key.
+func (o Object) Equal(other Object) bool {
+	return equalValues(o, other)
+}
+
+// Clone returns the copy of the object which can be modified independently.
+// The values of the props are shared between them.
+func (o Object) Clone() Object {
+	if o.m == nil {
+		return NewObject()
+	}
+
+	return Object{
+		m: o.m.Clone(),
+	}
+}
+
+// effectiveProps returns the props in the order without the ones which are followed by the ones with the same key.
+func (o Object) effectiveProps() []Prop {
+	props := make([]Prop, 0, o.Len())
+	if o.m == nil {
+		return props
+	}
+
+	o.m.Range(func(i int, key string, val interface{}) bool {
+		if o.m.Lookup(key) == i {
+			props = append(props, NewProp(key, propValue(val)))
+		}
+		return true
+	})
+
+	return props
+}
+
+// append appends the prop keeping the props with the same key.
+func (o *Object) append(prop Prop) {
+	if o.m == nil {
+		o.m = &orderedmap.Map{}
+	}
+
+	o.m.Append(string(prop.key), prop.val)
+}
+
+// propValue returns the value kept in orderedmap.Map, which is nil if the prop is given nil.
+func propValue(val interface{}) Value {
+	v, _ := val.(Value)
+	return v
+}
@@ -0,0 +1,245 @@
+package json
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/tomocy/go-cookbook/internal/orderedmap"
+)
+
+func TestObject(t *testing.T) {
+	tests := map[string]struct {
+		obj      Object
+		update   func(obj *Object)
+		expected Object
+	}{
+		"set to zero value": {
+			obj: Object{},
+			update: func(obj *Object) {
+				obj.Set("a", Num{literal: "1"})
+			},
+			expected: NewObject(NewProp("a", Num{literal: "1"})),
+		},
+		"set new key": {
+			obj: NewObject(NewProp("b", Num{literal: "1"})),
+			update: func(obj *Object) {
+				obj.Set("a", Num{literal: "2"})
+			},
+			expected: NewObject(NewProp("b", Num{literal: "1"}), NewProp("a", Num{literal: "2"})),
+		},
+		"set existing key in place": {
+			obj: NewObject(NewProp("a", Num{literal: "1"}), NewProp("b", Num{literal: "2"})),
+			update: func(obj *Object) {
+				obj.Set("a", Num{literal: "3"})
+			},
+			expected: NewObject(NewProp("a", Num{literal: "3"}), NewProp("b", Num{literal: "2"})),
+		},
+		"set duplicate key": {
+			obj: NewObject(NewProp("a", Num{literal: "1"}), NewProp("b", Num{literal: "2"}), NewProp("a", Num{literal: "3"})),
+			update: func(obj *Object) {
+				obj.Set("a", Num{literal: "4"})
+			},
+			expected: NewObject(NewProp("a", Num{literal: "1"}), NewProp("b", Num{literal: "2"}), NewProp("a", Num{literal: "4"})),
+		},
+		"delete": {
+			obj: NewObject(NewProp("a", Num{literal: "1"}), NewProp("b", Num{literal: "2"}), NewProp("c", Num{literal: "3"})),
+			update: func(obj *Object) {
+				obj.Delete("b")
+			},
+			expected: NewObject(NewProp("a", Num{literal: "1"}), NewProp("c", Num{literal: "3"})),
+		},
+		"delete duplicate key": {
+			obj: NewObject(NewProp("a", Num{literal: "1"}), NewProp("b", Num{literal: "2"}), NewProp("a", Num{literal: "3"})),
+			update: func(obj *Object) {
+				obj.Delete("a")
+			},
+			expected: NewObject(NewProp("b", Num{literal: "2"})),
+		},
+		"set deleted key": {
+			obj: NewObject(NewProp("a", Num{literal: "1"}), NewProp("b", Num{literal: "2"})),
+			update: func(obj *Object) {
+				obj.Delete("a")
+				obj.Set("a", Num{literal: "3"})
+			},
+			expected: NewObject(NewProp("b", Num{literal: "2"}), NewProp("a", Num{literal: "3"})),
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			test.update(&test.obj)
+			if err := assertObject(test.obj, test.expected); err != nil {
+				t.Errorf("should have updated: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestObjectGet(t *testing.T) {
+	obj := NewObject(
+		NewProp("a", Num{literal: "1"}),
+		NewProp("b", Num{literal: "2"}),
+		NewProp("a", Num{literal: "3"}),
+	)
+
+	tests := map[string]struct {
+		key      string
+		expected Value
+	}{
+		"key":           {key: "b", expected: Num{literal: "2"}},
+		"duplicate key": {key: "a", expected: Num{literal: "3"}},
+		"unknown key":   {key: "c"},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			actual, ok := obj.Get(test.key)
+			if ok != (test.expected != nil) || obj.Has(test.key) != ok {
+				t.Errorf("should have reported whether it has the key: %s", reportUnexpected("ok", ok, test.expected != nil))
+				return
+			}
+			if !ok {
+				return
+			}
+			if err := assertValue(actual, test.expected); err != nil {
+				t.Errorf("should have got: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestObjectWithManyProps(t *testing.T) {
+	obj := NewObject()
+	for i := 0; i < orderedmap.IndexThreshold*4; i++ {
+		obj.Set(fmt.Sprint(i), Num{literal: fmt.Sprint(i)})
+	}
+	for i := 0; i < orderedmap.IndexThreshold*4; i += 2 {
+		if !obj.Delete(fmt.Sprint(i)) {
+			t.Errorf("should have deleted %d", i)
+			return
+		}
+	}
+	obj.Set("1", String("one"))
+	obj.Set("0", String("zero"))
+
+	expected := NewObject()
+	for i := 1; i < orderedmap.IndexThreshold*4; i += 2 {
+		expected.Set(fmt.Sprint(i), Num{literal: fmt.Sprint(i)})
+	}
+	expected.Set("1", String("one"))
+	expected.Set("0", String("zero"))
+
+	if err := assertObject(obj, expected); err != nil {
+		t.Errorf("should have kept the order: %s", err)
+		return
+	}
+	for _, prop := range expected.Props() {
+		actual, ok := obj.Get(string(prop.key))
+		if !ok {
+			t.Errorf("should have got %q", prop.key)
+			return
+		}
+		if err := assertValue(actual, prop.val); err != nil {
+			t.Errorf("should have got %q: %s", prop.key, err)
+			return
+		}
+	}
+	if obj.Has("2") {
+		t.Errorf("should not have deleted key")
+		return
+	}
+}
+
+func TestObjectClone(t *testing.T) {
+	obj := NewObject(NewProp("a", Num{literal: "1"}), NewProp("b", Num{literal: "2"}))
+	cloned := obj.Clone()
+	cloned.Set("a", Num{literal: "3"})
+	cloned.Delete("b")
+	cloned.Set("c", Num{literal: "4"})
+
+	if err := assertObject(obj, NewObject(NewProp("a", Num{literal: "1"}), NewProp("b", Num{literal: "2"}))); err != nil {
+		t.Errorf("should not have modified the original: %s", err)
+		return
+	}
+	if err := assertObject(cloned, NewObject(NewProp("a", Num{literal: "3"}), NewProp("c", Num{literal: "4"}))); err != nil {
+		t.Errorf("should have modified the clone: %s", err)
+		return
+	}
+}
+
+func TestObjectToMap(t *testing.T) {
+	obj := NewObject(
+		NewProp("a", Num{literal: "1"}),
+		NewProp("b", NewObject(NewProp("c", Array{Bool(true), Null{}}))),
+		NewProp("a", String("two")),
+	)
+
+	expected := map[string]interface{}{
+		"a": "two",
+		"b": map[string]interface{}{
+			"c": []interface{}{true, nil},
+		},
+	}
+	if actual := obj.ToMap(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("should have converted: %s", reportUnexpected("map", actual, expected))
+		return
+	}
+}
+
+func TestObjectFromMap(t *testing.T) {
+	actual, err := ObjectFromMap(map[string]interface{}{
+		"b": 1,
+		"a": []interface{}{"two"},
+	})
+	if err != nil {
+		t.Errorf("should have converted: %s", err)
+		return
+	}
+
+	expected := NewObject(
+		NewProp("a", Array{String("two")}),
+		NewProp("b", Num{literal: "1"}),
+	)
+	if err := assertObject(actual, expected); err != nil {
+		t.Errorf("should have converted: %s", err)
+		return
+	}
+}
+
+func TestObjectEqual(t *testing.T) {
+	tests := map[string]struct {
+		obj, other Object
+		expected   bool
+	}{
+		"same props in different order": {
+			obj:      NewObject(NewProp("a", Num{literal: "1"}), NewProp("b", Num{literal: "2"})),
+			other:    NewObject(NewProp("b", Num{literal: "2.0"}), NewProp("a", Num{literal: "1"})),
+			expected: true,
+		},
+		"shadowed props": {
+			obj:      NewObject(NewProp("a", Num{literal: "1"}), NewProp("a", Num{literal: "2"})),
+			other:    NewObject(NewProp("a", Num{literal: "2"})),
+			expected: true,
+		},
+		"different values": {
+			obj:   NewObject(NewProp("a", Num{literal: "1"})),
+			other: NewObject(NewProp("a", Num{literal: "2"})),
+		},
+		"different keys": {
+			obj:   NewObject(NewProp("a", Num{literal: "1"})),
+			other: NewObject(NewProp("a", Num{literal: "1"}), NewProp("b", Num{literal: "2"})),
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			if actual := test.obj.Equal(test.other); actual != test.expected {
+				t.Errorf("should have compared: %s", reportUnexpected("equality", actual, test.expected))
+				return
+			}
+		})
+	}
+}
@@ -1,8 +1,8 @@
 package json
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
 )
 
 func newParser(lex lexer) parser {
@@ -17,49 +17,171 @@ func newParser(lex lexer) parser {
 
 type parser struct {
 	lex     lexer
+	prevTok token
 	currTok token
 	nextTok token
+
+	// path is the path to the value being parsed.
+	path valuePath
+	// positions records where each value starts keyed by its path if it is not nil.
+	positions map[string]pos
+
+	opts ParserOptions
+	// depth is the number of arrays and objects which enclose the value being parsed.
+	depth int
+	// tokenCount is the number of tokens which have been read as the current token.
+	tokenCount int
+
+	// recovering makes the parser record errors as diagnostics and go on parsing
+	// rather than stop at the first error.
+	recovering  bool
+	diagnostics []*SyntaxError
+}
+
+// parseDocument parses the whole source as a value, which is parse with the checks of the whole source.
+func (p *parser) parseDocument() (Value, error) {
+	val, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.checkTokenCount(); err != nil {
+		return nil, err
+	}
+	if (p.opts.DisallowTrailingData || p.recovering) && !p.doHaveToken(tokenEOF) {
+		// The token at which the value has been reported to be invalid is not reported again.
+		if n := len(p.diagnostics); n == 0 || p.diagnostics[n-1].Offset != p.currTok.pos.offset {
+			if err := p.recoverFrom(p.unexpectedTokenError("invalid document: document should have only one value", tokenEOF)); err != nil {
+				return nil, err
+			}
+		}
+		// The garbage right after the value is the one which has just been reported.
+		p.skipGarbage()
+		if err := p.skipTrailingData(); err != nil {
+			return nil, err
+		}
+	}
+
+	return val, nil
 }
 
-func (p *parser) parse() (value, error) {
+// skipTrailingData skips the tokens after the value of the document until EOF if the parser is recovering from errors,
+// where the trailing values are parsed only to report the errors in them.
+func (p *parser) skipTrailingData() error {
+	for !p.doHaveToken(tokenEOF) {
+		if err := p.checkTokenCount(); err != nil {
+			return err
+		}
+
+		if p.doHaveDelimiterToken() || p.doHaveToken(tokenColon) {
+			p.readToken()
+			continue
+		}
+		if _, err := p.parse(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parse parses the value which starts with the current token.
+// The value may be nil without any error only if the parser is recovering from errors,
+// which means that the value is missing or invalid.
+func (p *parser) parse() (Value, error) {
+	if err := p.checkTokenCount(); err != nil {
+		return nil, err
+	}
+
+	if p.positions != nil {
+		p.positions[p.path.String()] = p.currTok.pos
+	}
+
+	var val Value
+	var err error
 	switch p.currTok.kind {
 	case tokenLBracket:
 		return p.parseArray()
 	case tokenLBrace:
 		return p.parseObject()
 	case tokenNum:
-		return p.parseNum()
+		val, err = p.parseNum()
 	case tokenString:
-		return p.parseString()
+		val, err = p.parseString()
 	case tokenBool:
-		return p.parseBool()
+		val, err = p.parseBool()
+	case tokenNull:
+		val, err = p.parseNull()
 	default:
-		return nil, fmt.Errorf("unknown kind of token: %s", p.currTok.kind)
+		if err := p.recoverFrom(p.unexpectedTokenError("unknown kind of token", valueTokenKinds...)); err != nil {
+			return nil, err
+		}
+		// The garbage before the value is skipped if the value follows.
+		if p.skipGarbage() && p.doHaveValueToken() {
+			return p.parse()
+		}
+		return nil, nil
+	}
+	if err != nil {
+		if err := p.recoverFrom(err); err != nil {
+			return nil, err
+		}
+		// The invalid value is dropped.
+		p.readToken()
+		return nil, nil
 	}
+
+	return val, nil
 }
 
 func (p *parser) parseArray() (Array, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
 	p.readToken()
 
-	var arr Array
+	arr := Array{}
 	if p.doHaveToken(tokenRBracket) {
 		p.readToken()
 		return arr, nil
 	}
 	for {
+		if p.startsEnclosingProp() {
+			break
+		}
+
+		p.path = append(p.path, indexElem(len(arr)))
 		val, err := p.parse()
+		p.path = p.path[:len(p.path)-1]
 		if err != nil {
 			return nil, err
 		}
-		arr = append(arr, val)
+		if val != nil {
+			arr = append(arr, val)
+		}
 
+		if p.recoverSeparator("invalid array format: array should end with ']'", tokenRBracket) {
+			continue
+		}
 		if !p.doHaveToken(tokenComma) {
 			break
 		}
 		p.readToken()
+		if p.allowsTrailingComma() && p.doHaveToken(tokenRBracket) {
+			break
+		}
 	}
 	if !p.doHaveToken(tokenRBracket) {
-		return nil, fmt.Errorf("invalid array format: array should end with ']'")
+		if err := p.recoverFrom(p.unexpectedTokenError("invalid array format: array should end with ']'", tokenComma, tokenRBracket)); err != nil {
+			return nil, err
+		}
+		// The missing ']' is inserted.
+		if p.takesMismatchedClosing() {
+			p.readToken()
+		}
+		return arr, nil
 	}
 
 	p.readToken()
@@ -68,27 +190,47 @@ func (p *parser) parseArray() (Array, error) {
 }
 
 func (p *parser) parseObject() (Object, error) {
+	if err := p.enter(); err != nil {
+		return Object{}, err
+	}
+	defer p.leave()
+
 	p.readToken()
 
-	var obj Object
+	obj := NewObject()
 	if p.doHaveToken(tokenRBrace) {
 		p.readToken()
 		return obj, nil
 	}
 	for {
-		prop, err := p.parseProp()
+		prop, err := p.parseProp(obj)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse prop: %w", err)
+			return Object{}, fmt.Errorf("failed to parse prop: %w", err)
+		}
+		if prop.val != nil {
+			p.appendProp(&obj, prop)
 		}
-		obj = append(obj, prop)
 
+		if p.recoverSeparator("invalid object format: object should end with '}'", tokenRBrace) {
+			continue
+		}
 		if !p.doHaveToken(tokenComma) {
 			break
 		}
 		p.readToken()
+		if p.allowsTrailingComma() && p.doHaveToken(tokenRBrace) {
+			break
+		}
 	}
 	if !p.doHaveToken(tokenRBrace) {
-		return nil, fmt.Errorf("invalid object format: object should end with '}'")
+		if err := p.recoverFrom(p.unexpectedTokenError("invalid object format: object should end with '}'", tokenComma, tokenRBrace)); err != nil {
+			return Object{}, err
+		}
+		// The missing '}' is inserted.
+		if p.takesMismatchedClosing() {
+			p.readToken()
+		}
+		return obj, nil
 	}
 
 	p.readToken()
@@ -96,21 +238,67 @@ func (p *parser) parseObject() (Object, error) {
 	return obj, nil
 }
 
-func (p *parser) parseProp() (Prop, error) {
-	key, err := p.parseString()
+// parseProp parses the prop which starts with the current token.
+// The value of the prop may be nil without any error only if the parser is recovering from errors,
+// which means that the prop should be dropped.
+func (p *parser) parseProp(obj Object) (Prop, error) {
+	keyTok := p.currTok
+	key, ok, err := p.parseKey()
 	if err != nil {
-		return Prop{}, fmt.Errorf("failed to parse key: %w", err)
+		return Prop{}, err
+	}
+	if !ok {
+		// The value after the invalid key is parsed only to be skipped.
+		if !p.doHaveToken(tokenColon) {
+			return Prop{}, nil
+		}
+		p.readToken()
+		if _, err := p.parse(); err != nil {
+			return Prop{}, err
+		}
+		return Prop{}, nil
+	}
+
+	drops := false
+	if p.opts.DuplicateKeys != DuplicateKeysKeep && obj.Has(string(key)) {
+		if p.opts.DuplicateKeys == DuplicateKeysError {
+			if err := p.recoverFrom(p.withExcerpt(newSyntaxError(keyTok.pos, "duplicate key in object: %s", keyTok.literal))); err != nil {
+				return Prop{}, err
+			}
+		}
+		drops = p.opts.DuplicateKeys != DuplicateKeysLastWins
+		if drops {
+			// The positions of the value which is discarded should not overwrite the ones of the first value.
+			positions := p.positions
+			p.positions = nil
+			defer func() {
+				p.positions = positions
+			}()
+		}
 	}
 
 	if !p.doHaveToken(tokenColon) {
-		return Prop{}, fmt.Errorf("invalid prop format: prop should be composed of key and value separated by ':'")
+		if err := p.recoverFrom(p.unexpectedTokenError("invalid prop format: prop should be composed of key and value separated by ':'", tokenColon)); err != nil {
+			return Prop{}, err
+		}
+		// The missing ':' is inserted if the value follows, and otherwise the prop is dropped.
+		p.skipGarbage()
+		if !p.doHaveValueToken() {
+			return Prop{}, nil
+		}
+	} else {
+		p.readToken()
 	}
-	p.readToken()
 
+	p.path = append(p.path, keyElem(string(key)))
 	val, err := p.parse()
+	p.path = p.path[:len(p.path)-1]
 	if err != nil {
 		return Prop{}, fmt.Errorf("failed to parse value: %w", err)
 	}
+	if drops {
+		return Prop{}, nil
+	}
 
 	return Prop{
 		key: key,
@@ -118,23 +306,80 @@ func (p *parser) parseProp() (Prop, error) {
 	}, nil
 }
 
+// parseKey parses the key of the prop which starts with the current token.
+// It reports false without any error only if the parser is recovering from errors, which means that the key is invalid.
+func (p *parser) parseKey() (String, bool, error) {
+	if p.opts.Dialect == DialectJSON5 && !p.doHaveToken(tokenString) && isJSON5Identifier(p.currTok.literal) {
+		key := String(p.currTok.literal)
+		p.readToken()
+		return key, true, nil
+	}
+
+	if !p.doHaveToken(tokenString) {
+		if err := p.recoverFrom(p.unexpectedTokenError("invalid prop format: key should be string", tokenString)); err != nil {
+			return "", false, err
+		}
+		// The invalid key is skipped, where the value as the key is parsed to keep the brackets balanced.
+		switch {
+		case p.doHaveValueToken():
+			if _, err := p.parse(); err != nil {
+				return "", false, err
+			}
+		case !p.doHaveDelimiterToken() && !p.doHaveToken(tokenColon):
+			p.readToken()
+		}
+		return "", false, nil
+	}
+	key, err := p.parseString()
+	if err != nil {
+		if err := p.recoverFrom(err); err != nil {
+			return "", false, fmt.Errorf("failed to parse key: %w", err)
+		}
+		p.readToken()
+		return "", false, nil
+	}
+
+	return key, true, nil
+}
+
+// appendProp appends the prop to the object following the policy for duplicate keys.
+func (p *parser) appendProp(obj *Object, prop Prop) {
+	if p.opts.DuplicateKeys == DuplicateKeysLastWins && obj.Has(string(prop.key)) {
+		obj.Set(string(prop.key), prop.val)
+		return
+	}
+
+	obj.append(prop)
+}
+
 func (p *parser) parseNum() (Num, error) {
-	parsed, err := strconv.ParseInt(p.currTok.literal, 10, 32)
+	lit := p.currTok.literal
+	var err error
+	if p.opts.Dialect == DialectJSON5 {
+		lit, err = normalizeJSON5NumLiteral(lit)
+	} else {
+		err = validateNumLiteral(lit)
+	}
 	if err != nil {
-		return 0, err
+		return Num{}, p.withExcerpt(newSyntaxError(p.currTok.pos, "invalid number format: %s", err))
 	}
 
 	p.readToken()
 
-	return Num(parsed), nil
+	return Num{
+		literal: lit,
+	}, nil
 }
 
 func (p *parser) parseString() (String, error) {
-	if !isStringLiteralQuoted(p.currTok.literal) {
-		return "", fmt.Errorf("invalid string format: string should be quoted by '\"'")
+	// The lexer stops keeping the string once it turns out to be too long.
+	if p.currTok.tooLong {
+		return "", p.withExcerpt(newSyntaxError(p.currTok.pos, "too long string: string should be at most %d characters", p.opts.MaxStringLength))
+	}
+	unquoted, err := unquoteDialectStringLiteral(p.currTok.literal, p.currTok.pos, p.opts.Dialect)
+	if err != nil {
+		return "", p.withExcerpt(err)
 	}
-
-	unquoted := unquoteStringLiteral(p.currTok.literal)
 
 	p.readToken()
 
@@ -151,59 +396,249 @@ func (p *parser) parseBool() (Bool, error) {
 		return Bool(false), nil
 	}
 
-	return false, fmt.Errorf("unknown literal of bool: %s", p.currTok.literal)
+	return false, p.withExcerpt(newSyntaxError(p.currTok.pos, "unknown literal of bool: %s", p.currTok.literal))
+}
+
+func (p *parser) parseNull() (Null, error) {
+	if p.currTok.literal != literalNull {
+		return Null{}, p.withExcerpt(newSyntaxError(p.currTok.pos, "unknown literal of null: %s", p.currTok.literal))
+	}
+
+	p.readToken()
+
+	return Null{}, nil
+}
+
+// recoverFrom records the syntax error as a diagnostic and returns nil if the parser is recovering from errors,
+// and otherwise returns the error as it is.
+func (p *parser) recoverFrom(err error) error {
+	var syntaxErr *SyntaxError
+	if !p.recovering || !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	p.diagnostics = append(p.diagnostics, syntaxErr)
+
+	return nil
+}
+
+// recoverSeparator recovers from the error that the element or the prop which has just been parsed
+// is followed by neither ',' nor the closing token if the parser is recovering from errors.
+// It skips the garbage after the element or the prop, and reports whether ',' should be inserted
+// because the next element or prop follows.
+func (p *parser) recoverSeparator(msg string, closing tokenKind) bool {
+	if !p.recovering || p.doHaveToken(tokenComma) || p.doHaveToken(closing) {
+		return false
+	}
+	if closing == tokenRBracket && p.startsEnclosingProp() {
+		return false
+	}
+	startsNext := func() bool {
+		return p.doHaveValueToken() || closing == tokenRBrace && p.doHaveToken(tokenIdentifier)
+	}
+	if !startsNext() && p.doHaveDelimiterToken() {
+		return false
+	}
+	// The ',' has already been read if the array or the object which has just been parsed lacks the closing token.
+	if p.prevTok.kind == tokenComma && startsNext() {
+		return true
+	}
+
+	p.recoverFrom(p.unexpectedTokenError(msg, tokenComma, closing))
+	if startsNext() {
+		return true
+	}
+	p.skipGarbage()
+
+	return startsNext()
 }
 
-func unquoteStringLiteral(s string) string {
-	if !isStringLiteralQuoted(s) {
-		return ""
+// startsEnclosingProp reports whether the current token looks like the start of the next prop
+// of the object which encloses the array being parsed, which means that the array lacks ']'.
+func (p *parser) startsEnclosingProp() bool {
+	if !p.recovering || !p.doHaveToken(tokenString) || p.nextTok.kind != tokenColon {
+		return false
+	}
+	for _, elem := range p.path {
+		if !elem.isIndex {
+			return true
+		}
 	}
 
-	return s[1 : len(s)-1]
+	return false
 }
 
-func isStringLiteralQuoted(s string) bool {
-	if len(s) < 2 {
+// takesMismatchedClosing reports whether the array or the object being parsed, which lacks its closing token,
+// should take the current token as the typo of it if the current token is the other closing token.
+// It should not if the enclosing value ends with the token, which is followed by what can follow the enclosing value,
+// so that the enclosing value does not reuse the token leaving the rest unparsed.
+func (p *parser) takesMismatchedClosing() bool {
+	if !p.doHaveToken(tokenRBracket) && !p.doHaveToken(tokenRBrace) {
+		return false
+	}
+
+	n := len(p.path)
+	if n == 0 || closingOf(p.path[n-1]) != p.currTok.kind {
+		return true
+	}
+
+	switch next := p.nextTok.kind; {
+	case next == tokenEOF:
 		return false
+	case n == 1:
+		// The enclosing value is the whole document, which nothing should follow.
+		return true
+	default:
+		return next != tokenComma && next != closingOf(p.path[n-2])
+	}
+}
+
+// closingOf returns the closing token of the array or the object which has the value at the elem.
+func closingOf(elem pathElem) tokenKind {
+	if elem.isIndex {
+		return tokenRBracket
+	}
+
+	return tokenRBrace
+}
+
+// skipGarbage skips the tokens which are neither the starts of values nor delimiters,
+// and reports whether any token has been skipped.
+func (p *parser) skipGarbage() bool {
+	skipped := false
+	for !p.doHaveValueToken() && !p.doHaveDelimiterToken() {
+		p.readToken()
+		skipped = true
+	}
+
+	return skipped
+}
+
+func (p parser) allowsTrailingComma() bool {
+	return p.opts.Dialect == DialectJSONC || p.opts.Dialect == DialectJSON5
+}
+
+func (p *parser) enter() error {
+	if max := p.opts.MaxDepth; max > 0 && p.depth >= max {
+		return p.withExcerpt(newSyntaxError(p.currTok.pos, "too deep nesting: arrays and objects should nest at most %d levels", max))
+	}
+	p.depth++
+
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) checkTokenCount() error {
+	if max := p.opts.MaxTokens; max > 0 && p.tokenCount > max {
+		return p.withExcerpt(newSyntaxError(p.currTok.pos, "too many tokens: document should have at most %d tokens", max))
 	}
 
-	return s[0] == '"' && s[len(s)-1] == '"'
+	return nil
+}
+
+func (p *parser) unexpectedTokenError(msg string, expected ...tokenKind) *SyntaxError {
+	return p.withExcerpt(newUnexpectedTokenError(p.currTok, msg, expected...))
+}
+
+func (p *parser) withExcerpt(err *SyntaxError) *SyntaxError {
+	err.Excerpt = p.lex.excerpt(pos{
+		line:  err.Line - 1,
+		start: err.Column - 1,
+	})
+
+	return err
 }
 
 func (p *parser) readToken() {
+	p.prevTok = p.currTok
 	p.currTok = p.nextTok
 	p.nextTok = p.lex.readToken()
+
+	if p.currTok.kind != "" && p.currTok.kind != tokenEOF {
+		p.tokenCount++
+	}
 }
 
 func (p parser) doHaveToken(kind tokenKind) bool {
 	return p.currTok.kind == kind
 }
 
-type value interface {
+func (p parser) doHaveValueToken() bool {
+	for _, kind := range valueTokenKinds {
+		if p.doHaveToken(kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// doHaveDelimiterToken reports whether the current token delimits values, at which the parser resynchronizes.
+func (p parser) doHaveDelimiterToken() bool {
+	return p.doHaveToken(tokenComma) || p.doHaveToken(tokenRBracket) || p.doHaveToken(tokenRBrace) || p.doHaveToken(tokenEOF)
+}
+
+// Value is a JSON value.
+// Its implementations are Null, Bool, Num, String, Array and Object.
+type Value interface {
+	Kind() Kind
 	value()
 }
 
-type Num int
+type Kind string
 
-func (Num) value() {}
+const (
+	KindNull   Kind = "null"
+	KindBool   Kind = "bool"
+	KindNum    Kind = "number"
+	KindString Kind = "string"
+	KindArray  Kind = "array"
+	KindObject Kind = "object"
+)
+
+type Null struct{}
+
+func (Null) Kind() Kind { return KindNull }
+
+func (Null) value() {}
 
 type String string
 
+func (String) Kind() Kind { return KindString }
+
 func (String) value() {}
 
 type Bool bool
 
+func (Bool) Kind() Kind { return KindBool }
+
 func (Bool) value() {}
 
-type Array []value
+type Array []Value
 
-func (Array) value() {}
+func (Array) Kind() Kind { return KindArray }
 
-type Object []Prop
+func (Array) value() {}
 
-func (Object) value() {}
+func NewProp(key string, val Value) Prop {
+	return Prop{
+		key: String(key),
+		val: val,
+	}
+}
 
 type Prop struct {
 	key String
-	val value
+	val Value
+}
+
+func (p Prop) Key() string {
+	return string(p.key)
+}
+
+func (p Prop) Value() Value {
+	return p.val
 }
@@ -2,22 +2,40 @@ package json
 
 import (
 	"fmt"
+	"math"
+	"strings"
 	"testing"
 )
 
 func TestParse(t *testing.T) {
 	tests := map[string]struct {
 		src      string
-		expected value
+		expected Value
 	}{
 		"int": {
 			src:      "1",
-			expected: Num(1),
+			expected: Num{literal: "1"},
+		},
+		"negative fraction with exponent": {
+			src:      "-12.5e+3",
+			expected: Num{literal: "-12.5e+3"},
+		},
+		"int over int64": {
+			src:      "123456789012345678901234567890",
+			expected: Num{literal: "123456789012345678901234567890"},
 		},
 		"string": {
 			src:      `"aiueo"`,
 			expected: String("aiueo"),
 		},
+		"string with escapes": {
+			src:      `"a\"b\\c\/d\b\f\n\r\t"`,
+			expected: String("a\"b\\c/d\b\f\n\r\t"),
+		},
+		"string with unicode escapes": {
+			src:      `"\u3042\u00E9\ud83d\ude00"`,
+			expected: String("あé😀"),
+		},
 		"true": {
 			src:      "true",
 			expected: Bool(true),
@@ -26,25 +44,29 @@ func TestParse(t *testing.T) {
 			src:      "false",
 			expected: Bool(false),
 		},
+		"null": {
+			src:      "null",
+			expected: Null{},
+		},
 		"empty array": {
 			src:      `[]`,
 			expected: Array{},
 		},
 		"array": {
-			src: `[1, "two", 3, "four", [1, "two"], {"a": 1, "b": "two", "c": true}, false]`,
+			src: `[1, "two", 3, "four", [1, "two"], {"a": 1, "b": "two", "c": true}, false, null]`,
 			expected: Array{
-				Num(1),
+				Num{literal: "1"},
 				String("two"),
-				Num(3),
+				Num{literal: "3"},
 				String("four"),
 				Array{
-					Num(1),
+					Num{literal: "1"},
 					String("two"),
 				},
-				Object{
+				NewObject(
 					Prop{
 						key: String("a"),
-						val: Num(1),
+						val: Num{literal: "1"},
 					},
 					Prop{
 						key: String("b"),
@@ -54,20 +76,21 @@ func TestParse(t *testing.T) {
 						key: String("c"),
 						val: Bool(true),
 					},
-				},
+				),
 				Bool(false),
+				Null{},
 			},
 		},
 		"empty object": {
 			src:      `{}`,
-			expected: Object{},
+			expected: NewObject(),
 		},
 		"object": {
-			src: `{"a": 1, "b": "two", "c": 3, "d": "four", "e": [1, "two", 3], "f": {"a": 1, "b": "two", "c": false}, "g": true}`,
-			expected: Object{
+			src: `{"a": 1, "b": "two", "c": 3, "d": "four", "e": [1, "two", 3], "f": {"a": 1, "b": "two", "c": false}, "g": true, "h": null}`,
+			expected: NewObject(
 				Prop{
 					key: String("a"),
-					val: Num(1),
+					val: Num{literal: "1"},
 				},
 				Prop{
 					key: String("b"),
@@ -75,7 +98,7 @@ func TestParse(t *testing.T) {
 				},
 				Prop{
 					key: String("c"),
-					val: Num(3),
+					val: Num{literal: "3"},
 				},
 				Prop{
 					key: String("d"),
@@ -84,17 +107,17 @@ func TestParse(t *testing.T) {
 				Prop{
 					key: String("e"),
 					val: Array{
-						Num(1),
+						Num{literal: "1"},
 						String("two"),
-						Num(3),
+						Num{literal: "3"},
 					},
 				},
 				Prop{
 					key: String("f"),
-					val: Object{
+					val: NewObject(
 						Prop{
 							key: String("a"),
-							val: Num(1),
+							val: Num{literal: "1"},
 						},
 						Prop{
 							key: String("b"),
@@ -104,19 +127,23 @@ func TestParse(t *testing.T) {
 							key: String("c"),
 							val: Bool(false),
 						},
-					},
+					),
 				},
 				Prop{
 					key: String("g"),
 					val: Bool(true),
 				},
-			},
+				Prop{
+					key: String("h"),
+					val: Null{},
+				},
+			),
 		},
 	}
 
 	for n, test := range tests {
 		t.Run(n, func(t *testing.T) {
-			lex := newLexer([]rune(test.src))
+			lex := newLexer(strings.NewReader(test.src))
 			p := newParser(lex)
 
 			actual, err := p.parse()
@@ -132,7 +159,537 @@ func TestParse(t *testing.T) {
 	}
 }
 
-func assertValue(actual, expected value) error {
+func TestParseInvalidString(t *testing.T) {
+	tests := map[string]string{
+		"unterminated":             `"aiueo`,
+		"invalid escape":           `"a\xb"`,
+		"incomplete unicode":       `"\u12"`,
+		"invalid hex digit":        `"\u12g4"`,
+		"lone high surrogate":      `"\ud83d"`,
+		"lone low surrogate":       `"\ude00"`,
+		"high surrogate with char": `"\ud83d\u0041"`,
+		"control character":        "\"a\tb\"",
+	}
+
+	for n, src := range tests {
+		t.Run(n, func(t *testing.T) {
+			lex := newLexer(strings.NewReader(src))
+			p := newParser(lex)
+
+			if _, err := p.parse(); err == nil {
+				t.Errorf("should have failed to parse: %s", src)
+				return
+			}
+		})
+	}
+}
+
+func TestParseInvalidNum(t *testing.T) {
+	tests := map[string]string{
+		"leading zero":          "01",
+		"only minus":            "-",
+		"plus":                  "+1",
+		"no digits after point": "1.",
+		"no digits in exponent": "1e+",
+		"point without int":     "-.5",
+		"multiple points":       "1.2.3",
+		"minus in the middle":   "1-2",
+	}
+
+	for n, src := range tests {
+		t.Run(n, func(t *testing.T) {
+			lex := newLexer(strings.NewReader(src))
+			p := newParser(lex)
+
+			if _, err := p.parse(); err == nil {
+				t.Errorf("should have failed to parse: %s", src)
+				return
+			}
+		})
+	}
+}
+
+func TestParserOptionsParse(t *testing.T) {
+	tests := map[string]struct {
+		opts     ParserOptions
+		src      string
+		expected string
+	}{
+		"trailing whitespaces": {
+			opts:     ParserOptions{DisallowTrailingData: true},
+			src:      "[1] \n\t",
+			expected: `[1]`,
+		},
+		"trailing data allowed": {
+			opts:     ParserOptions{},
+			src:      `[1] garbage`,
+			expected: `[1]`,
+		},
+		"duplicate keys kept": {
+			opts:     ParserOptions{},
+			src:      `{"a": 1, "b": 2, "a": 3}`,
+			expected: `{"a":1,"b":2,"a":3}`,
+		},
+		"first wins": {
+			opts:     ParserOptions{DuplicateKeys: DuplicateKeysFirstWins},
+			src:      `{"a": 1, "b": 2, "a": 3}`,
+			expected: `{"a":1,"b":2}`,
+		},
+		"last wins": {
+			opts:     ParserOptions{DuplicateKeys: DuplicateKeysLastWins},
+			src:      `{"a": 1, "b": 2, "a": 3, "a": 4}`,
+			expected: `{"a":4,"b":2}`,
+		},
+		"duplicate keys in different objects": {
+			opts:     ParserOptions{DuplicateKeys: DuplicateKeysError},
+			src:      `{"a": {"a": 1}, "b": [{"a": 2}, {"a": 3}]}`,
+			expected: `{"a":{"a":1},"b":[{"a":2},{"a":3}]}`,
+		},
+		"max depth": {
+			opts:     ParserOptions{MaxDepth: 2},
+			src:      `[{"a": 1}, [2]]`,
+			expected: `[{"a":1},[2]]`,
+		},
+		"max string length": {
+			opts:     ParserOptions{MaxStringLength: 3},
+			src:      `{"abc": "あいう", "d": "\u0041\u0042\u0043"}`,
+			expected: `{"abc":"あいう","d":"ABC"}`,
+		},
+		"max document size": {
+			opts:     ParserOptions{MaxDocumentSize: 7},
+			src:      `[1, 2] `,
+			expected: `[1,2]`,
+		},
+		"max tokens": {
+			opts:     ParserOptions{MaxTokens: 5},
+			src:      `[1, 2]`,
+			expected: `[1,2]`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			val, err := test.opts.ParseString(test.src)
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+
+			actual, err := Marshal(val)
+			if err != nil {
+				t.Errorf("should have marshaled: %s", err)
+				return
+			}
+			if string(actual) != test.expected {
+				t.Errorf("should have parsed: %s", reportUnexpected("value", string(actual), test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestParserOptionsParseFails(t *testing.T) {
+	tests := map[string]struct {
+		opts     ParserOptions
+		src      string
+		expected string
+	}{
+		"trailing data": {
+			opts:     ParserOptions{DisallowTrailingData: true},
+			src:      `[1] garbage`,
+			expected: "syntax error at line 1, column 5: invalid document: document should have only one value: expected 'EOF', but found 'illegal'",
+		},
+		"trailing value": {
+			opts:     ParserOptions{DisallowTrailingData: true},
+			src:      `{} {}`,
+			expected: "syntax error at line 1, column 4: invalid document: document should have only one value: expected 'EOF', but found '{'",
+		},
+		"duplicate keys": {
+			opts:     ParserOptions{DuplicateKeys: DuplicateKeysError},
+			src:      `{"a": 1, "b": 2, "a": 3}`,
+			expected: `failed to parse prop: syntax error at line 1, column 18: duplicate key in object: "a"`,
+		},
+		"too deep": {
+			opts:     ParserOptions{MaxDepth: 2},
+			src:      `[[[1]]]`,
+			expected: "syntax error at line 1, column 3: too deep nesting: arrays and objects should nest at most 2 levels",
+		},
+		"too long string": {
+			opts:     ParserOptions{MaxStringLength: 3},
+			src:      `["abcd"]`,
+			expected: "syntax error at line 1, column 2: too long string: string should be at most 3 characters",
+		},
+		"too long key": {
+			opts:     ParserOptions{MaxStringLength: 3},
+			src:      `{"abcd": 1}`,
+			expected: "failed to parse prop: failed to parse key: syntax error at line 1, column 2: too long string: string should be at most 3 characters",
+		},
+		"too large document": {
+			opts:     ParserOptions{MaxDocumentSize: 6},
+			src:      `[1, 2] `,
+			expected: "failed to read: document should be at most 6 bytes",
+		},
+		"too many tokens": {
+			opts:     ParserOptions{MaxTokens: 4},
+			src:      `[1, 2, 3]`,
+			expected: "syntax error at line 1, column 8: too many tokens: document should have at most 4 tokens",
+		},
+		"too many closing tokens": {
+			opts:     ParserOptions{MaxTokens: 4},
+			src:      `[[1]]`,
+			expected: "syntax error at line 1, column 6: too many tokens: document should have at most 4 tokens",
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			_, err := test.opts.ParseString(test.src)
+			if err == nil {
+				t.Errorf("should have failed to parse: %s", test.src)
+				return
+			}
+			if err.Error() != test.expected {
+				t.Errorf("should have returned the error: %s", reportUnexpected("error", err.Error(), test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestParseDialect(t *testing.T) {
+	tests := map[string]struct {
+		dialect  Dialect
+		src      string
+		expected string
+	}{
+		"line comments": {
+			dialect: DialectJSONC,
+			src: `// config
+{
+	"a": 1, // one
+	"b": "// not comment"
+}
+// end`,
+			expected: `{"a":1,"b":"// not comment"}`,
+		},
+		"block comments": {
+			dialect:  DialectJSONC,
+			src:      "/* head */[1, /* multi\nline */ 2/**/]/***/",
+			expected: `[1,2]`,
+		},
+		"trailing commas": {
+			dialect:  DialectJSONC,
+			src:      `{"a": [1, 2,], "b": {"c": 3,},}`,
+			expected: `{"a":[1,2],"b":{"c":3}}`,
+		},
+		"json5 comments and trailing commas": {
+			dialect:  DialectJSON5,
+			src:      "[1, // one\n 2,]",
+			expected: `[1,2]`,
+		},
+		"unquoted keys": {
+			dialect:  DialectJSON5,
+			src:      `{a: 1, $b_2: 2, true: 3, Infinity: 4, キー: 5}`,
+			expected: `{"a":1,"$b_2":2,"true":3,"Infinity":4,"キー":5}`,
+		},
+		"single quoted strings": {
+			dialect:  DialectJSON5,
+			src:      `['a"b', 'c\'d', "e'f"]`,
+			expected: `["a\"b","c'd","e'f"]`,
+		},
+		"json5 escapes": {
+			dialect:  DialectJSON5,
+			src:      "['\\x41\\v\\0\\q', 'a\\\nb', 'c\\\r\nd', '\tt']",
+			expected: `["A\u000b\u0000q","ab","cd","\tt"]`,
+		},
+		"hex numbers": {
+			dialect:  DialectJSON5,
+			src:      `[0x1F, -0XFF, +0x0, 0xffffffffffffffffff]`,
+			expected: `[31,-255,0,4722366482869645213695]`,
+		},
+		"decimal points and signs": {
+			dialect:  DialectJSON5,
+			src:      `[.5, 5., -.5e1, +1, +1.5E+2, 5.e3]`,
+			expected: `[0.5,5,-0.5e1,1,1.5E+2,5e3]`,
+		},
+		"json5 whitespaces": {
+			dialect:  DialectJSON5,
+			src:      "\ufeff[1,\u00a0\v\f2\u2028]",
+			expected: `[1,2]`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			val, err := ParserOptions{Dialect: test.dialect}.ParseString(test.src)
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+
+			actual, err := Marshal(val)
+			if err != nil {
+				t.Errorf("should have marshaled: %s", err)
+				return
+			}
+			if string(actual) != test.expected {
+				t.Errorf("should have parsed: %s", reportUnexpected("value", string(actual), test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestParseJSON5NonFiniteNumbers(t *testing.T) {
+	val, err := ParserOptions{Dialect: DialectJSON5}.ParseString(`[Infinity, -Infinity, +Infinity, NaN, -NaN]`)
+	if err != nil {
+		t.Errorf("should have parsed: %s", err)
+		return
+	}
+
+	expected := []float64{math.Inf(1), math.Inf(-1), math.Inf(1), math.NaN(), math.NaN()}
+	for i, elem := range val.(Array) {
+		actual, err := elem.(Num).Float64()
+		if err != nil {
+			t.Errorf("should have converted %s into float64: %s", elem, err)
+			return
+		}
+		if actual != expected[i] && !(math.IsNaN(actual) && math.IsNaN(expected[i])) {
+			t.Errorf("should have parsed the number: %s", reportUnexpected("number", actual, expected[i]))
+			return
+		}
+	}
+
+	if _, err := Marshal(val); err == nil {
+		t.Errorf("should have failed to marshal non finite numbers")
+		return
+	}
+}
+
+func TestParseInvalidDialect(t *testing.T) {
+	tests := map[string]struct {
+		dialect Dialect
+		src     string
+	}{
+		"comment in json":               {dialect: DialectJSON, src: "[1] // one"},
+		"trailing comma in json":        {dialect: DialectJSON, src: "[1,]"},
+		"unterminated block comment":    {dialect: DialectJSONC, src: "[1] /* one"},
+		"only comma":                    {dialect: DialectJSONC, src: "[,]"},
+		"double trailing commas":        {dialect: DialectJSONC, src: "[1,,]"},
+		"unquoted key in jsonc":         {dialect: DialectJSONC, src: "{a: 1}"},
+		"single quoted string in jsonc": {dialect: DialectJSONC, src: "['a']"},
+		"hex number in jsonc":           {dialect: DialectJSONC, src: "0x1F"},
+		"unquoted string value":         {dialect: DialectJSON5, src: "{a: b}"},
+		"key starting with digit":       {dialect: DialectJSON5, src: "{1a: 1}"},
+		"mismatched quotes":             {dialect: DialectJSON5, src: `['a"]`},
+		"line terminator in string":     {dialect: DialectJSON5, src: "['a\nb']"},
+		"octal escape":                  {dialect: DialectJSON5, src: `['\01']`},
+		"incomplete hex escape":         {dialect: DialectJSON5, src: `['\x4']`},
+		"only point":                    {dialect: DialectJSON5, src: "[.]"},
+		"invalid hex number":            {dialect: DialectJSON5, src: "0xG"},
+		"leading zero":                  {dialect: DialectJSON5, src: "01"},
+		"lower case infinity":           {dialect: DialectJSON5, src: "infinity"},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			opts := ParserOptions{Dialect: test.dialect, DisallowTrailingData: true}
+			if _, err := opts.ParseString(test.src); err == nil {
+				t.Errorf("should have failed to parse: %s", test.src)
+				return
+			}
+		})
+	}
+}
+
+func TestParserOptionsParseWithLimitsOnLargeDocument(t *testing.T) {
+	src := strings.Repeat("[", 1<<20)
+
+	_, err := ParserOptions{MaxDepth: 64}.ParseString(src)
+	if err == nil {
+		t.Errorf("should have failed to parse too deep document")
+		return
+	}
+	if !strings.Contains(err.Error(), "too deep nesting") {
+		t.Errorf("should have failed because of the depth: %s", err)
+		return
+	}
+}
+
+func TestParseRecovering(t *testing.T) {
+	tests := map[string]struct {
+		src         string
+		expected    string
+		diagnostics []string
+	}{
+		"valid": {
+			src:      `{"a": [1, true]}`,
+			expected: `{"a":[1,true]}`,
+		},
+		"missing closing bracket": {
+			src:         `[1, 2`,
+			expected:    `[1,2]`,
+			diagnostics: []string{"1:6: invalid array format: array should end with ']'"},
+		},
+		"missing comma": {
+			src:         `[1 2]`,
+			expected:    `[1,2]`,
+			diagnostics: []string{"1:4: invalid array format: array should end with ']'"},
+		},
+		"missing element": {
+			src:         `[1,,2]`,
+			expected:    `[1,2]`,
+			diagnostics: []string{"1:4: unknown kind of token"},
+		},
+		"garbage element": {
+			src:         `[1, foo bar, 2]`,
+			expected:    `[1,2]`,
+			diagnostics: []string{"1:5: unknown kind of token"},
+		},
+		"garbage before element": {
+			src:         `[1, foo 2]`,
+			expected:    `[1,2]`,
+			diagnostics: []string{"1:5: unknown kind of token"},
+		},
+		"mismatched closing bracket": {
+			src:         `{"a": [1, 2}`,
+			expected:    `{"a":[1,2]}`,
+			diagnostics: []string{"1:12: invalid array format: array should end with ']'"},
+		},
+		"mismatched closing bracket followed by props": {
+			src:      `{"a": [1}, "b": tru, "c": 2}`,
+			expected: `{"a":[1],"c":2}`,
+			diagnostics: []string{
+				"1:9: invalid array format: array should end with ']'",
+				"1:17: unknown kind of token",
+			},
+		},
+		"mismatched closing brace followed by elements": {
+			src:         `[{"a": 1], 2]`,
+			expected:    `[{"a":1},2]`,
+			diagnostics: []string{"1:9: invalid object format: object should end with '}'"},
+		},
+		"trailing data": {
+			src:         `[1] garbage`,
+			expected:    `[1]`,
+			diagnostics: []string{"1:5: invalid document: document should have only one value"},
+		},
+		"trailing values": {
+			src:      `{"a": 1}}, "b": tru}`,
+			expected: `{"a":1}`,
+			diagnostics: []string{
+				"1:9: invalid document: document should have only one value",
+				"1:17: unknown kind of token",
+			},
+		},
+		"missing colon": {
+			src:         `{"a" 1}`,
+			expected:    `{"a":1}`,
+			diagnostics: []string{"1:6: invalid prop format: prop should be composed of key and value separated by ':'"},
+		},
+		"missing value": {
+			src:         `{"a", "b": 1, "c": }`,
+			expected:    `{"b":1}`,
+			diagnostics: []string{"1:5: invalid prop format: prop should be composed of key and value separated by ':'", "1:20: unknown kind of token"},
+		},
+		"invalid key": {
+			src:         `{a: 1, [2]: 3, "b": 4}`,
+			expected:    `{"b":4}`,
+			diagnostics: []string{"1:2: invalid prop format: key should be string", "1:8: invalid prop format: key should be string"},
+		},
+		"invalid values": {
+			src:      `{"a": 01, "b": "\x", "c": 1}`,
+			expected: `{"c":1}`,
+			diagnostics: []string{
+				`1:7: invalid number format: "01" should not have leading zeros`,
+				`1:17: invalid string format: invalid escape sequence '\x'`,
+			},
+		},
+		"trailing comma": {
+			src:         `{"a": 1,}`,
+			expected:    `{"a":1}`,
+			diagnostics: []string{"1:9: invalid prop format: key should be string"},
+		},
+		"duplicate key": {
+			src:         `{"a": 1, "a": 2}`,
+			expected:    `{"a":1}`,
+			diagnostics: []string{`1:10: duplicate key in object: "a"`},
+		},
+		"no value": {
+			src:         `]`,
+			expected:    `null`,
+			diagnostics: []string{"1:1: unknown kind of token"},
+		},
+		"hand edited file": {
+			src: `{
+  "name": "app"
+  "deps": [1 2,
+  "version": tru,
+  "tags": ["a", "b"
+}`,
+			expected: `{"name":"app","deps":[1,2],"tags":["a","b"]}`,
+			diagnostics: []string{
+				"3:3: invalid object format: object should end with '}'",
+				"3:14: invalid array format: array should end with ']'",
+				"4:3: invalid array format: array should end with ']'",
+				"4:14: unknown kind of token",
+				"6:1: invalid array format: array should end with ']'",
+			},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			opts := ParserOptions{
+				DuplicateKeys: DuplicateKeysError,
+			}
+			val, diagnostics := opts.ParseRecovering([]byte(test.src))
+
+			actual := "null"
+			if val != nil {
+				actual = compactString(val)
+			}
+			if actual != test.expected {
+				t.Errorf("should have returned the best-effort value: %s", reportUnexpected("value", actual, test.expected))
+				return
+			}
+
+			actualDiagnostics := make([]string, len(diagnostics))
+			for i, d := range diagnostics {
+				actualDiagnostics[i] = fmt.Sprintf("%d:%d: %s", d.Line, d.Column, d.Msg)
+			}
+			if strings.Join(actualDiagnostics, "\n") != strings.Join(test.diagnostics, "\n") {
+				t.Errorf("should have reported the diagnostics: %s", reportUnexpected("diagnostics", actualDiagnostics, test.diagnostics))
+				return
+			}
+		})
+	}
+}
+
+func TestParseRecoveringStopsAtLimits(t *testing.T) {
+	val, diagnostics := ParserOptions{MaxDepth: 2}.ParseRecovering([]byte(`[1 2, [[3]]]`))
+	if val != nil {
+		t.Errorf("should not have returned any value: %s", reportUnexpected("value", val, nil))
+		return
+	}
+
+	expected := []string{
+		"invalid array format: array should end with ']'",
+		"too deep nesting: arrays and objects should nest at most 2 levels",
+	}
+	if len(diagnostics) != len(expected) {
+		t.Errorf("should have reported the diagnostics: %s", reportUnexpected("len of diagnostics", len(diagnostics), len(expected)))
+		return
+	}
+	for i, d := range diagnostics {
+		if d.Msg != expected[i] {
+			t.Errorf("should have reported the diagnostic: %s", reportUnexpected("msg", d.Msg, expected[i]))
+			return
+		}
+	}
+}
+
+func assertValue(actual, expected Value) error {
 	switch expected := expected.(type) {
 	case Array:
 		if err := assertArray(actual.(Array), expected); err != nil {
@@ -163,6 +720,12 @@ func assertValue(actual, expected value) error {
 			return fmt.Errorf("unexpected bool: %s", err)
 		}
 
+		return nil
+	case Null:
+		if _, ok := actual.(Null); !ok {
+			return fmt.Errorf("unexpected null: %s", reportUnexpected("type", fmt.Sprintf("%T", actual), "Null"))
+		}
+
 		return nil
 	default:
 		return fmt.Errorf("development error: unknown type of value: %T", expected)
@@ -183,11 +746,12 @@ func assertArray(actual, expected Array) error {
 }
 
 func assertObject(actual, expected Object) error {
-	if len(actual) != len(expected) {
-		return reportUnexpected("len of value", len(actual), len(expected))
+	if actual.Len() != expected.Len() {
+		return reportUnexpected("len of value", actual.Len(), expected.Len())
 	}
-	for i, expected := range expected {
-		if err := assertProp(actual[i], expected); err != nil {
+	actualProps := actual.Props()
+	for i, expected := range expected.Props() {
+		if err := assertProp(actualProps[i], expected); err != nil {
 			return fmt.Errorf("unexpected prop at %d: %s", i, err)
 		}
 	}
@@ -0,0 +1,416 @@
+package json
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Patch is JSON Patch defined in RFC 6902 which is a sequence of operations.
+type Patch []Operation
+
+// PatchOp is the kind of operation of Patch.
+type PatchOp string
+
+const (
+	PatchAdd     PatchOp = "add"
+	PatchRemove  PatchOp = "remove"
+	PatchReplace PatchOp = "replace"
+	PatchMove    PatchOp = "move"
+	PatchCopy    PatchOp = "copy"
+	PatchTest    PatchOp = "test"
+)
+
+// Operation is an operation of Patch.
+type Operation struct {
+	Op   PatchOp
+	Path Pointer
+	// From is the source of move and copy.
+	From Pointer
+	// Value is the value for add, replace and test.
+	// It is nil if the operation does not have it, which is different from Null.
+	Value Value
+}
+
+// ParsePatch parses the given JSON text as Patch.
+func ParsePatch(src []byte) (Patch, error) {
+	val, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Patch
+	if err := p.UnmarshalJSONValue(val); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// UnmarshalJSONValue converts the given array of operation objects into Patch.
+func (p *Patch) UnmarshalJSONValue(val Value) error {
+	arr, ok := val.(Array)
+	if !ok {
+		return fmt.Errorf("failed to convert %s into patch: patch should be array", kindOf(val))
+	}
+
+	patch := make(Patch, len(arr))
+	for i, elem := range arr {
+		if err := patch[i].UnmarshalJSONValue(elem); err != nil {
+			return fmt.Errorf("failed to convert operation %d: %w", i, err)
+		}
+	}
+	*p = patch
+
+	return nil
+}
+
+// MarshalJSONValue converts the patch into array of operation objects.
+func (p Patch) MarshalJSONValue() (Value, error) {
+	arr := make(Array, len(p))
+	for i, op := range p {
+		val, err := op.MarshalJSONValue()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert operation %d: %w", i, err)
+		}
+		arr[i] = val
+	}
+
+	return arr, nil
+}
+
+// UnmarshalJSONValue converts the given object such as {"op": "add", "path": "/a", "value": 1} into Operation.
+// It ignores unknown members.
+func (o *Operation) UnmarshalJSONValue(val Value) error {
+	obj, ok := val.(Object)
+	if !ok {
+		return fmt.Errorf("operation should be object, but %s", kindOf(val))
+	}
+
+	var op Operation
+	if val, ok := obj.Get("op"); ok {
+		s, ok := val.(String)
+		if !ok {
+			return fmt.Errorf("op should be string")
+		}
+		op.Op = PatchOp(s)
+	}
+
+	for _, member := range []struct {
+		name string
+		dst  *Pointer
+	}{
+		{name: "path", dst: &op.Path},
+		{name: "from", dst: &op.From},
+	} {
+		val, ok := obj.Get(member.name)
+		if !ok {
+			continue
+		}
+
+		s, ok := val.(String)
+		if !ok {
+			return fmt.Errorf("%s should be string", member.name)
+		}
+		ptr, err := ParsePointer(string(s))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", member.name, err)
+		}
+		*member.dst = ptr
+	}
+
+	if val, ok := obj.Get("value"); ok {
+		op.Value = val
+	}
+
+	if err := op.validate(); err != nil {
+		return err
+	}
+	*o = op
+
+	return nil
+}
+
+// MarshalJSONValue converts the operation into object.
+func (o Operation) MarshalJSONValue() (Value, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	obj := NewObject(
+		NewProp("op", String(o.Op)),
+		NewProp("path", String(o.Path.String())),
+	)
+	if o.Op.hasFrom() {
+		obj.Set("from", String(o.From.String()))
+	}
+	if o.Op.hasValue() {
+		obj.Set("value", o.Value)
+	}
+
+	return obj, nil
+}
+
+func (o Operation) validate() error {
+	switch o.Op {
+	case PatchAdd, PatchRemove, PatchReplace, PatchMove, PatchCopy, PatchTest:
+	case "":
+		return fmt.Errorf("operation should have op")
+	default:
+		return fmt.Errorf("unknown op: %s", o.Op)
+	}
+
+	if o.Path == nil {
+		return fmt.Errorf("%s operation should have path", o.Op)
+	}
+	if o.Op.hasFrom() && o.From == nil {
+		return fmt.Errorf("%s operation should have from", o.Op)
+	}
+	if o.Op.hasValue() && o.Value == nil {
+		return fmt.Errorf("%s operation should have value", o.Op)
+	}
+
+	return nil
+}
+
+func (op PatchOp) hasFrom() bool {
+	return op == PatchMove || op == PatchCopy
+}
+
+func (op PatchOp) hasValue() bool {
+	return op == PatchAdd || op == PatchReplace || op == PatchTest
+}
+
+// Apply applies the operations in order to the given value.
+// The application is atomic: it returns the patched copy of the given value if all the operations succeed,
+// and the given value is never modified even if any of them fails.
+func (p Patch) Apply(doc Value) (Value, error) {
+	for i, op := range p {
+		applied, err := op.Apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply operation %d: %w", i, err)
+		}
+		doc = applied
+	}
+
+	return doc, nil
+}
+
+// Apply applies the operation to the given value and returns the patched copy of it.
+func (o Operation) Apply(doc Value) (Value, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	switch o.Op {
+	case PatchAdd:
+		return o.Path.Add(doc, o.Value)
+	case PatchRemove:
+		return o.Path.Remove(doc)
+	case PatchReplace:
+		return o.Path.Set(doc, o.Value)
+	case PatchMove:
+		if o.From.isProperPrefixOf(o.Path) {
+			return nil, fmt.Errorf("failed to move %s to %s: value cannot be moved into its child", o.From, o.Path)
+		}
+
+		val, err := o.From.Get(doc)
+		if err != nil {
+			return nil, err
+		}
+		removed, err := o.From.Remove(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		return o.Path.Add(removed, val)
+	case PatchCopy:
+		val, err := o.From.Get(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		return o.Path.Add(doc, val)
+	case PatchTest:
+		val, err := o.Path.Get(doc)
+		if err != nil {
+			return nil, err
+		}
+		if !equalValues(val, o.Value) {
+			return nil, fmt.Errorf("failed to test %s: value is not equal to the expected one", o.Path)
+		}
+
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown op: %s", o.Op)
+	}
+}
+
+// Diff returns the patch which transforms from into to
+// with the minimum number of add, remove and replace operations.
+func Diff(from, to Value) Patch {
+	return diff(Pointer{}, from, to, nil)
+}
+
+func diff(at Pointer, from, to Value, patch Patch) Patch {
+	if equalValues(from, to) {
+		return patch
+	}
+
+	switch from := from.(type) {
+	case Object:
+		if to, ok := to.(Object); ok {
+			return diffObjects(at, from, to, patch)
+		}
+	case Array:
+		if to, ok := to.(Array); ok {
+			return diffArrays(at, from, to, patch)
+		}
+	}
+
+	return append(patch, Operation{
+		Op:    PatchReplace,
+		Path:  at,
+		Value: to,
+	})
+}
+
+func diffObjects(at Pointer, from, to Object, patch Patch) Patch {
+	// Only the last one of the props with the same key is effective.
+	for _, prop := range from.effectiveProps() {
+		key := string(prop.key)
+		if val, ok := to.Get(key); ok {
+			patch = diff(at.appended(key), prop.val, val, patch)
+			continue
+		}
+
+		patch = append(patch, Operation{
+			Op:   PatchRemove,
+			Path: at.appended(key),
+		})
+	}
+
+	for _, prop := range to.effectiveProps() {
+		key := string(prop.key)
+		if from.Has(key) {
+			continue
+		}
+
+		patch = append(patch, Operation{
+			Op:    PatchAdd,
+			Path:  at.appended(key),
+			Value: prop.val,
+		})
+	}
+
+	return patch
+}
+
+// maxDiffArraysCosts is the max number of the costs which diffArrays computes to find the minimum edit distance.
+// The arrays which need more costs than it are diffed element by element at the same indexes.
+const maxDiffArraysCosts = 1 << 20
+
+// diffArrays generates the operations following the edit script with the minimum edit distance.
+func diffArrays(at Pointer, from, to Array, patch Patch) Patch {
+	if (len(from)+1)*(len(to)+1) > maxDiffArraysCosts {
+		return diffArraysByIndex(at, from, to, patch)
+	}
+
+	// costs[i][j] is the number of operations to transform from[i:] into to[j:].
+	costs := make([][]int, len(from)+1)
+	for i := range costs {
+		costs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from); i >= 0; i-- {
+		for j := len(to); j >= 0; j-- {
+			switch {
+			case i == len(from):
+				costs[i][j] = len(to) - j
+			case j == len(to):
+				costs[i][j] = len(from) - i
+			case equalValues(from[i], to[j]):
+				costs[i][j] = costs[i+1][j+1]
+			default:
+				costs[i][j] = 1 + minInt(costs[i+1][j+1], costs[i+1][j], costs[i][j+1])
+			}
+		}
+	}
+
+	// The elements before j have already been the ones of to.
+	i, j := 0, 0
+	for i < len(from) || j < len(to) {
+		elemAt := at.appended(strconv.Itoa(j))
+		switch {
+		case i < len(from) && j < len(to) && equalValues(from[i], to[j]):
+			i, j = i+1, j+1
+		case i < len(from) && j < len(to) && costs[i][j] == 1+costs[i+1][j+1]:
+			patch = diffElems(elemAt, from[i], to[j], patch)
+			i, j = i+1, j+1
+		case i < len(from) && (j == len(to) || costs[i][j] == 1+costs[i+1][j]):
+			patch = append(patch, Operation{
+				Op:   PatchRemove,
+				Path: elemAt,
+			})
+			i++
+		default:
+			patch = append(patch, Operation{
+				Op:    PatchAdd,
+				Path:  elemAt,
+				Value: to[j],
+			})
+			j++
+		}
+	}
+
+	return patch
+}
+
+// diffArraysByIndex generates the operations which transform the elements at the same indexes,
+// and then add or remove the elements beyond the shorter one.
+func diffArraysByIndex(at Pointer, from, to Array, patch Patch) Patch {
+	n := minInt(len(from), len(to))
+	for i := 0; i < n; i++ {
+		if !equalValues(from[i], to[i]) {
+			patch = diffElems(at.appended(strconv.Itoa(i)), from[i], to[i], patch)
+		}
+	}
+	for i := n; i < len(to); i++ {
+		patch = append(patch, Operation{
+			Op:    PatchAdd,
+			Path:  at.appended(strconv.Itoa(i)),
+			Value: to[i],
+		})
+	}
+	for i := len(from) - 1; i >= n; i-- {
+		patch = append(patch, Operation{
+			Op:   PatchRemove,
+			Path: at.appended(strconv.Itoa(i)),
+		})
+	}
+
+	return patch
+}
+
+// diffElems generates the operations which transform the element of array with one operation,
+// which is the one in the element if possible rather than replacing the whole element.
+func diffElems(at Pointer, from, to Value, patch Patch) Patch {
+	if inner := diff(at, from, to, nil); len(inner) == 1 {
+		return append(patch, inner...)
+	}
+
+	return append(patch, Operation{
+		Op:    PatchReplace,
+		Path:  at,
+		Value: to,
+	})
+}
+
+func minInt(first int, rest ...int) int {
+	min := first
+	for _, n := range rest {
+		if n < min {
+			min = n
+		}
+	}
+
+	return min
+}
@@ -0,0 +1,363 @@
+package json
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestPatchApply(t *testing.T) {
+	tests := map[string]struct {
+		doc      string
+		patch    string
+		expected string
+	}{
+		"add prop": {
+			doc:      `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			expected: `{"foo": "bar", "baz": "qux"}`,
+		},
+		"add element": {
+			doc:      `{"foo": ["bar", "baz"]}`,
+			patch:    `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			expected: `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		"append element": {
+			doc:      `{"foo": ["bar"]}`,
+			patch:    `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			expected: `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+		"add null": {
+			doc:      `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": null}]`,
+			expected: `{"foo": "bar", "baz": null}`,
+		},
+		"remove prop": {
+			doc:      `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "remove", "path": "/baz"}]`,
+			expected: `{"foo": "bar"}`,
+		},
+		"remove element": {
+			doc:      `{"foo": ["bar", "qux", "baz"]}`,
+			patch:    `[{"op": "remove", "path": "/foo/1"}]`,
+			expected: `{"foo": ["bar", "baz"]}`,
+		},
+		"replace": {
+			doc:      `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			expected: `{"baz": "boo", "foo": "bar"}`,
+		},
+		"replace whole document": {
+			doc:      `{"baz": "qux"}`,
+			patch:    `[{"op": "replace", "path": "", "value": [1]}]`,
+			expected: `[1]`,
+		},
+		"move prop": {
+			doc:      `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			expected: `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		"move element": {
+			doc:      `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch:    `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			expected: `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		"copy": {
+			doc:      `{"foo": {"bar": [1]}}`,
+			patch:    `[{"op": "copy", "from": "/foo/bar", "path": "/baz"}]`,
+			expected: `{"foo": {"bar": [1]}, "baz": [1]}`,
+		},
+		"test": {
+			doc:      `{"baz": "qux", "foo": ["a", 2, "c"], "n": {"a": 1.0, "b": 2}}`,
+			patch:    `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}, {"op": "test", "path": "/n", "value": {"b": 2, "a": 1}}]`,
+			expected: `{"baz": "qux", "foo": ["a", 2, "c"], "n": {"a": 1.0, "b": 2}}`,
+		},
+		"escaped path": {
+			doc:      `{"/": 9, "~1": 10}`,
+			patch:    `[{"op": "test", "path": "/~01", "value": 10}, {"op": "remove", "path": "/~1"}]`,
+			expected: `{"~1": 10}`,
+		},
+		"operations in order": {
+			doc:      `{"a": []}`,
+			patch:    `[{"op": "add", "path": "/a/-", "value": 1}, {"op": "add", "path": "/a/-", "value": 2}, {"op": "move", "from": "/a/0", "path": "/b"}]`,
+			expected: `{"a": [2], "b": 1}`,
+		},
+		"unknown members": {
+			doc:      `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			expected: `{"foo": "bar", "baz": "qux"}`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			doc, err := ParseString(test.doc)
+			if err != nil {
+				t.Errorf("should have parsed the document: %s", err)
+				return
+			}
+			patch, err := ParsePatch([]byte(test.patch))
+			if err != nil {
+				t.Errorf("should have parsed the patch: %s", err)
+				return
+			}
+			expected, err := ParseString(test.expected)
+			if err != nil {
+				t.Errorf("should have parsed the expected document: %s", err)
+				return
+			}
+
+			actual, err := patch.Apply(doc)
+			if err != nil {
+				t.Errorf("should have applied: %s", err)
+				return
+			}
+			if err := assertValue(actual, expected); err != nil {
+				t.Errorf("should have applied: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestPatchApplyFails(t *testing.T) {
+	tests := map[string]struct {
+		doc   string
+		patch string
+	}{
+		"add to missing parent": {
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+		},
+		"remove missing prop": {
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "remove", "path": "/baz"}]`,
+		},
+		"replace missing prop": {
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "/baz", "value": 1}]`,
+		},
+		"move into its child": {
+			doc:   `{"foo": {"bar": 1}}`,
+			patch: `[{"op": "move", "from": "/foo", "path": "/foo/bar/baz"}]`,
+		},
+		"copy from missing prop": {
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "copy", "from": "/baz", "path": "/qux"}]`,
+		},
+		"test unequal value": {
+			doc:   `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch: `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+		},
+		"test number against string": {
+			doc:   `{"foo": ["a", 10]}`,
+			patch: `[{"op": "test", "path": "/foo/1", "value": "10"}]`,
+		},
+		"index out of range": {
+			doc:   `{"foo": ["a"]}`,
+			patch: `[{"op": "add", "path": "/foo/2", "value": "b"}]`,
+		},
+		"failure after success": {
+			doc:   `{"foo": ["a"]}`,
+			patch: `[{"op": "replace", "path": "/foo/0", "value": "b"}, {"op": "remove", "path": "/bar"}]`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			doc, err := ParseString(test.doc)
+			if err != nil {
+				t.Errorf("should have parsed the document: %s", err)
+				return
+			}
+			patch, err := ParsePatch([]byte(test.patch))
+			if err != nil {
+				t.Errorf("should have parsed the patch: %s", err)
+				return
+			}
+			original, _ := ParseString(test.doc)
+
+			if _, err := patch.Apply(doc); err == nil {
+				t.Errorf("should have failed to apply")
+				return
+			}
+			if err := assertValue(doc, original); err != nil {
+				t.Errorf("should not have modified the given value: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestParseInvalidPatch(t *testing.T) {
+	tests := map[string]string{
+		"not array":            `{"op": "add", "path": "/a", "value": 1}`,
+		"without op":           `[{"path": "/a", "value": 1}]`,
+		"unknown op":           `[{"op": "merge", "path": "/a", "value": 1}]`,
+		"without path":         `[{"op": "remove"}]`,
+		"invalid path":         `[{"op": "remove", "path": "a"}]`,
+		"without from":         `[{"op": "move", "path": "/a"}]`,
+		"without value":        `[{"op": "add", "path": "/a"}]`,
+		"non string op":        `[{"op": 1, "path": "/a", "value": 1}]`,
+		"non object operation": `[1]`,
+	}
+
+	for n, src := range tests {
+		t.Run(n, func(t *testing.T) {
+			if _, err := ParsePatch([]byte(src)); err == nil {
+				t.Errorf("should have failed to parse: %s", src)
+				return
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := map[string]struct {
+		from, to string
+		expected string
+	}{
+		"equal": {
+			from:     `{"a": [1, {"b": 2}], "c": 1}`,
+			to:       `{"c": 1.0, "a": [1, {"b": 2}]}`,
+			expected: `[]`,
+		},
+		"scalar": {
+			from:     `1`,
+			to:       `"1"`,
+			expected: `[{"op":"replace","path":"","value":"1"}]`,
+		},
+		"props": {
+			from:     `{"a": 1, "b": {"c": 2, "d": 3}, "e": 4}`,
+			to:       `{"a": 1, "b": {"c": 5, "d": 3}, "f": 6}`,
+			expected: `[{"op":"replace","path":"/b/c","value":5},{"op":"remove","path":"/e"},{"op":"add","path":"/f","value":6}]`,
+		},
+		"escaped keys": {
+			from:     `{"a/b": 1}`,
+			to:       `{"a/b": 2, "c~d": 3}`,
+			expected: `[{"op":"replace","path":"/a~1b","value":2},{"op":"add","path":"/c~0d","value":3}]`,
+		},
+		"inserted element": {
+			from:     `[1, 2, 3]`,
+			to:       `[1, 4, 2, 3]`,
+			expected: `[{"op":"add","path":"/1","value":4}]`,
+		},
+		"removed elements": {
+			from:     `[1, 2, 3, 4]`,
+			to:       `[2, 4]`,
+			expected: `[{"op":"remove","path":"/0"},{"op":"remove","path":"/1"}]`,
+		},
+		"appended element": {
+			from:     `[1]`,
+			to:       `[1, 2]`,
+			expected: `[{"op":"add","path":"/1","value":2}]`,
+		},
+		"replaced element": {
+			from:     `[1, 2, 3]`,
+			to:       `[1, 5, 3]`,
+			expected: `[{"op":"replace","path":"/1","value":5}]`,
+		},
+		"changed element": {
+			from:     `[{"a": 1, "b": 2}, 3]`,
+			to:       `[{"a": 1, "b": 4}, 3]`,
+			expected: `[{"op":"replace","path":"/0/b","value":4}]`,
+		},
+		"element changed much": {
+			from:     `[{"a": 1, "b": 2}]`,
+			to:       `[{"a": 3, "b": 4}]`,
+			expected: `[{"op":"replace","path":"/0","value":{"a":3,"b":4}}]`,
+		},
+		"kind changed": {
+			from:     `{"a": [1]}`,
+			to:       `{"a": {"0": 1}}`,
+			expected: `[{"op":"replace","path":"/a","value":{"0":1}}]`,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			from, err := ParseString(test.from)
+			if err != nil {
+				t.Errorf("should have parsed from: %s", err)
+				return
+			}
+			to, err := ParseString(test.to)
+			if err != nil {
+				t.Errorf("should have parsed to: %s", err)
+				return
+			}
+
+			patch := Diff(from, to)
+			marshaled, err := Marshal(patch)
+			if err != nil {
+				t.Errorf("should have marshaled the patch: %s", err)
+				return
+			}
+			if string(marshaled) != test.expected {
+				t.Errorf("should have generated the patch: %s", reportUnexpected("patch", string(marshaled), test.expected))
+				return
+			}
+
+			applied, err := patch.Apply(from)
+			if err != nil {
+				t.Errorf("should have applied the generated patch: %s", err)
+				return
+			}
+			if !equalValues(applied, to) {
+				t.Errorf("should have transformed from into to: %s", reportUnexpected("value", applied, to))
+				return
+			}
+		})
+	}
+}
+
+func TestDiffLargeArrays(t *testing.T) {
+	tests := map[string]struct {
+		from, to Array
+		expected int
+	}{
+		"same lengths": {
+			from:     largeArray(2000, 0),
+			to:       largeArray(2000, 1),
+			expected: 2000,
+		},
+		"longer to": {
+			from:     largeArray(1500, 0),
+			to:       largeArray(2000, 0),
+			expected: 500,
+		},
+		"shorter to": {
+			from:     largeArray(2000, 0),
+			to:       largeArray(1500, 0),
+			expected: 500,
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			patch := Diff(test.from, test.to)
+			if len(patch) != test.expected {
+				t.Errorf("should have diffed element by element: %s", reportUnexpected("number of operations", len(patch), test.expected))
+				return
+			}
+
+			applied, err := patch.Apply(test.from)
+			if err != nil {
+				t.Errorf("should have applied the generated patch: %s", err)
+				return
+			}
+			if !equalValues(applied, test.to) {
+				t.Errorf("should have transformed from into to")
+				return
+			}
+		})
+	}
+}
+
+func largeArray(n, offset int) Array {
+	arr := make(Array, n)
+	for i := range arr {
+		arr[i] = Num{literal: strconv.Itoa(i + offset)}
+	}
+
+	return arr
+}
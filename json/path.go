@@ -0,0 +1,174 @@
+package json
+
+import (
+	"strconv"
+	"strings"
+)
+
+// valuePath locates a value in a JSON document from its root.
+type valuePath []pathElem
+
+// pathElem is either a key of an object or an index of an array.
+// It can also be a filter such as ?@.id==1 which identifies an element of an array by its content.
+type pathElem struct {
+	key     string
+	index   int
+	isIndex bool
+	filter  string
+}
+
+func keyElem(key string) pathElem {
+	return pathElem{
+		key: key,
+	}
+}
+
+func indexElem(index int) pathElem {
+	return pathElem{
+		index:   index,
+		isIndex: true,
+	}
+}
+
+func filterElem(filter string) pathElem {
+	return pathElem{
+		filter: filter,
+	}
+}
+
+func (p valuePath) appended(elem pathElem) valuePath {
+	appended := make(valuePath, len(p), len(p)+1)
+	copy(appended, p)
+
+	return append(appended, elem)
+}
+
+// String returns the path in the form such as $.items[0]["first name"].
+// Filters are written in the form such as $.items[?@.id==1].
+func (p valuePath) String() string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, elem := range p {
+		switch {
+		case elem.isIndex:
+			b.WriteByte('[')
+			b.WriteString(strconv.Itoa(elem.index))
+			b.WriteByte(']')
+		case elem.filter != "":
+			b.WriteString("[?")
+			b.WriteString(elem.filter)
+			b.WriteByte(']')
+		default:
+			writeKeyElem(&b, elem.key)
+		}
+	}
+
+	return b.String()
+}
+
+// pattern returns the path in the same form as String
+// except that every index and filter is written as [*].
+func (p valuePath) pattern() string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, elem := range p {
+		if elem.isIndex || elem.filter != "" {
+			b.WriteString("[*]")
+			continue
+		}
+		writeKeyElem(&b, elem.key)
+	}
+
+	return b.String()
+}
+
+func writeKeyElem(b *strings.Builder, key string) {
+	if isIdentifier(key) {
+		b.WriteByte('.')
+		b.WriteString(key)
+		return
+	}
+
+	b.WriteByte('[')
+	b.WriteString(quoteString(key, false, false))
+	b.WriteByte(']')
+}
+
+// normalized returns the path in the form of normalized path of RFC 9535 such as $['items'][0]['first name'].
+// The path should not have filters.
+func (p valuePath) normalized() string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, elem := range p {
+		b.WriteByte('[')
+		if elem.isIndex {
+			b.WriteString(strconv.Itoa(elem.index))
+		} else {
+			writeNormalizedName(&b, elem.key)
+		}
+		b.WriteByte(']')
+	}
+
+	return b.String()
+}
+
+func writeNormalizedName(b *strings.Builder, name string) {
+	b.WriteByte('\'')
+	for _, c := range name {
+		switch c {
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\'', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		default:
+			if isControlChar(c) {
+				writeUnicodeEscape(b, c)
+				continue
+			}
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('\'')
+}
+
+// pointer returns the path as Pointer.
+// The path should not have filters.
+func (p valuePath) pointer() Pointer {
+	ptr := make(Pointer, len(p))
+	for i, elem := range p {
+		if elem.isIndex {
+			ptr[i] = strconv.Itoa(elem.index)
+			continue
+		}
+		ptr[i] = elem.key
+	}
+
+	return ptr
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		if c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' {
+			continue
+		}
+		if i != 0 && isNum(c) {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
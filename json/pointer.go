@@ -0,0 +1,314 @@
+package json
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pointer is JSON Pointer defined in RFC 6901 which consists of reference tokens.
+// The empty Pointer references the whole document.
+type Pointer []string
+
+// ParsePointer parses the given string such as /a/b~1c/0 as Pointer.
+func ParsePointer(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("failed to parse pointer %q: pointer should start with '/'", s)
+	}
+
+	splitted := strings.Split(s[1:], "/")
+	ptr := make(Pointer, len(splitted))
+	for i, tok := range splitted {
+		unescaped, err := unescapePointerToken(tok)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pointer %q: %w", s, err)
+		}
+		ptr[i] = unescaped
+	}
+
+	return ptr, nil
+}
+
+func unescapePointerToken(tok string) (string, error) {
+	if !strings.Contains(tok, "~") {
+		return tok, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(tok); i++ {
+		if tok[i] != '~' {
+			b.WriteByte(tok[i])
+			continue
+		}
+
+		if i+1 >= len(tok) {
+			return "", fmt.Errorf("invalid escape in %q: '~' should be followed by '0' or '1'", tok)
+		}
+		i++
+		switch tok[i] {
+		case '0':
+			b.WriteByte('~')
+		case '1':
+			b.WriteByte('/')
+		default:
+			return "", fmt.Errorf("invalid escape in %q: '~' should be followed by '0' or '1'", tok)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// String returns the pointer in the form such as /a/b~1c/0.
+func (p Pointer) String() string {
+	var b strings.Builder
+	for _, tok := range p {
+		b.WriteByte('/')
+		b.WriteString(escapePointerToken(tok))
+	}
+
+	return b.String()
+}
+
+var pointerTokenEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapePointerToken(tok string) string {
+	return pointerTokenEscaper.Replace(tok)
+}
+
+func (p Pointer) appended(tok string) Pointer {
+	appended := make(Pointer, len(p), len(p)+1)
+	copy(appended, p)
+
+	return append(appended, tok)
+}
+
+func (p Pointer) isProperPrefixOf(other Pointer) bool {
+	if len(p) >= len(other) {
+		return false
+	}
+	for i := range p {
+		if p[i] != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Get returns the value referenced by the pointer in the given value.
+func (p Pointer) Get(root Value) (Value, error) {
+	curr := root
+	for i, tok := range p {
+		child, err := childOf(curr, tok, p[:i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", p, err)
+		}
+		curr = child
+	}
+
+	return curr, nil
+}
+
+// Set replaces the value referenced by the pointer in the given value with the new one.
+// The referenced value should exist.
+//
+// Set, Add and Remove do not modify the given value but return the updated copy of it,
+// which shares the untouched values with the given one.
+func (p Pointer) Set(root Value, val Value) (Value, error) {
+	if len(p) == 0 {
+		return val, nil
+	}
+
+	updated, err := p.update(root, 0, func(parent Value, tok string, at Pointer) (Value, error) {
+		return setChild(parent, tok, at, val)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set %s: %w", p, err)
+	}
+
+	return updated, nil
+}
+
+// Add adds the new value at the pointer in the given value in the same way as the add operation of RFC 6902.
+// A value is inserted before the referenced element for arrays, where the token "-" references the end,
+// and a prop is added or replaced for objects.
+func (p Pointer) Add(root Value, val Value) (Value, error) {
+	if len(p) == 0 {
+		return val, nil
+	}
+
+	updated, err := p.update(root, 0, func(parent Value, tok string, at Pointer) (Value, error) {
+		return addChild(parent, tok, at, val)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add %s: %w", p, err)
+	}
+
+	return updated, nil
+}
+
+// Remove removes the value referenced by the pointer from the given value.
+// The referenced value should exist.
+func (p Pointer) Remove(root Value) (Value, error) {
+	if len(p) == 0 {
+		return nil, fmt.Errorf("failed to remove %s: the whole document cannot be removed", p)
+	}
+
+	updated, err := p.update(root, 0, removeChild)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove %s: %w", p, err)
+	}
+
+	return updated, nil
+}
+
+// update returns the copy of the given value at the depth
+// where the parent of the value referenced by the pointer is replaced with what the given function returns.
+func (p Pointer) update(curr Value, depth int, fn func(parent Value, tok string, at Pointer) (Value, error)) (Value, error) {
+	tok, at := p[depth], p[:depth]
+	if depth == len(p)-1 {
+		return fn(curr, tok, at)
+	}
+
+	child, err := childOf(curr, tok, at)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := p.update(child, depth+1, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return setChild(curr, tok, at, updated)
+}
+
+func childOf(parent Value, tok string, at Pointer) (Value, error) {
+	switch parent := parent.(type) {
+	case Object:
+		val, ok := parent.Get(tok)
+		if !ok {
+			return nil, fmt.Errorf("%q is not found in object at %q", tok, at)
+		}
+		return val, nil
+	case Array:
+		i, err := arrayIndex(tok, len(parent), at, false)
+		if err != nil {
+			return nil, err
+		}
+		return parent[i], nil
+	default:
+		return nil, fmt.Errorf("%s at %q cannot have %q", kindOf(parent), at, tok)
+	}
+}
+
+func setChild(parent Value, tok string, at Pointer, val Value) (Value, error) {
+	switch parent := parent.(type) {
+	case Object:
+		if !parent.Has(tok) {
+			return nil, fmt.Errorf("%q is not found in object at %q", tok, at)
+		}
+
+		obj := parent.Clone()
+		obj.Set(tok, val)
+
+		return obj, nil
+	case Array:
+		i, err := arrayIndex(tok, len(parent), at, false)
+		if err != nil {
+			return nil, err
+		}
+
+		arr := make(Array, len(parent))
+		copy(arr, parent)
+		arr[i] = val
+
+		return arr, nil
+	default:
+		return nil, fmt.Errorf("%s at %q cannot have %q", kindOf(parent), at, tok)
+	}
+}
+
+func addChild(parent Value, tok string, at Pointer, val Value) (Value, error) {
+	switch parent := parent.(type) {
+	case Object:
+		obj := parent.Clone()
+		obj.Set(tok, val)
+
+		return obj, nil
+	case Array:
+		i, err := arrayIndex(tok, len(parent), at, true)
+		if err != nil {
+			return nil, err
+		}
+
+		arr := make(Array, 0, len(parent)+1)
+		arr = append(arr, parent[:i]...)
+		arr = append(arr, val)
+
+		return append(arr, parent[i:]...), nil
+	default:
+		return nil, fmt.Errorf("%s at %q cannot have %q", kindOf(parent), at, tok)
+	}
+}
+
+func removeChild(parent Value, tok string, at Pointer) (Value, error) {
+	switch parent := parent.(type) {
+	case Object:
+		obj := parent.Clone()
+		if !obj.Delete(tok) {
+			return nil, fmt.Errorf("%q is not found in object at %q", tok, at)
+		}
+
+		return obj, nil
+	case Array:
+		i, err := arrayIndex(tok, len(parent), at, false)
+		if err != nil {
+			return nil, err
+		}
+
+		arr := make(Array, 0, len(parent)-1)
+		arr = append(arr, parent[:i]...)
+
+		return append(arr, parent[i+1:]...), nil
+	default:
+		return nil, fmt.Errorf("%s at %q cannot have %q", kindOf(parent), at, tok)
+	}
+}
+
+// arrayIndex returns the index which the given token references in the array of the given length.
+// The end of the array can be referenced by the token "-" or its length only if the end is allowed.
+func arrayIndex(tok string, length int, at Pointer, allowsEnd bool) (int, error) {
+	if tok == "-" {
+		if !allowsEnd {
+			return 0, fmt.Errorf("index - of array at %q references nonexistent element", at)
+		}
+		return length, nil
+	}
+
+	if tok == "" || len(tok) > 1 && tok[0] == '0' {
+		return 0, fmt.Errorf("invalid index of array at %q: %q", at, tok)
+	}
+	for _, c := range tok {
+		if !isNum(c) {
+			return 0, fmt.Errorf("invalid index of array at %q: %q", at, tok)
+		}
+	}
+
+	i, err := strconv.Atoi(tok)
+	if err != nil || i > length || i == length && !allowsEnd {
+		return 0, fmt.Errorf("index %s is out of range of array of length %d at %q", tok, length, at)
+	}
+
+	return i, nil
+}
+
+func kindOf(val Value) Kind {
+	if val == nil {
+		return KindNull
+	}
+
+	return val.Kind()
+}
@@ -0,0 +1,381 @@
+package json
+
+import (
+	"testing"
+)
+
+func TestParsePointer(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected Pointer
+	}{
+		"whole document": {
+			src:      "",
+			expected: Pointer{},
+		},
+		"empty key": {
+			src:      "/",
+			expected: Pointer{""},
+		},
+		"keys and index": {
+			src:      "/a/b/0",
+			expected: Pointer{"a", "b", "0"},
+		},
+		"escapes": {
+			src:      "/a~1b/m~0n/~01",
+			expected: Pointer{"a/b", "m~n", "~1"},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			actual, err := ParsePointer(test.src)
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+			if err := assertPointer(actual, test.expected); err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+			if actual.String() != test.src {
+				t.Errorf("should have given back the source: %s", reportUnexpected("string", actual.String(), test.src))
+				return
+			}
+		})
+	}
+}
+
+func TestParseInvalidPointer(t *testing.T) {
+	tests := map[string]string{
+		"without leading slash": "a/b",
+		"tilde at last":         "/a~",
+		"unknown escape":        "/a~2",
+	}
+
+	for n, src := range tests {
+		t.Run(n, func(t *testing.T) {
+			if _, err := ParsePointer(src); err == nil {
+				t.Errorf("should have failed to parse: %s", src)
+				return
+			}
+		})
+	}
+}
+
+func TestPointerGet(t *testing.T) {
+	doc := NewObject(
+		Prop{key: "foo", val: Array{String("bar"), String("baz")}},
+		Prop{key: "", val: Num{literal: "0"}},
+		Prop{key: "a/b", val: Num{literal: "1"}},
+		Prop{key: "m~n", val: Num{literal: "8"}},
+		Prop{key: "dup", val: Num{literal: "1"}},
+		Prop{key: "dup", val: Num{literal: "2"}},
+	)
+
+	tests := map[string]struct {
+		ptr      string
+		expected Value
+	}{
+		"whole document": {
+			ptr:      "",
+			expected: doc,
+		},
+		"array": {
+			ptr:      "/foo",
+			expected: Array{String("bar"), String("baz")},
+		},
+		"element": {
+			ptr:      "/foo/0",
+			expected: String("bar"),
+		},
+		"empty key": {
+			ptr:      "/",
+			expected: Num{literal: "0"},
+		},
+		"escaped slash": {
+			ptr:      "/a~1b",
+			expected: Num{literal: "1"},
+		},
+		"escaped tilde": {
+			ptr:      "/m~0n",
+			expected: Num{literal: "8"},
+		},
+		"duplicated key": {
+			ptr:      "/dup",
+			expected: Num{literal: "2"},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			ptr, err := ParsePointer(test.ptr)
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+
+			actual, err := ptr.Get(doc)
+			if err != nil {
+				t.Errorf("should have got: %s", err)
+				return
+			}
+			if err := assertValue(actual, test.expected); err != nil {
+				t.Errorf("should have got: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestPointerGetFails(t *testing.T) {
+	doc := NewObject(
+		Prop{key: "foo", val: Array{String("bar"), String("baz")}},
+	)
+
+	tests := map[string]string{
+		"missing key":        "/bar",
+		"out of range":       "/foo/2",
+		"end of array":       "/foo/-",
+		"leading zero":       "/foo/01",
+		"non number index":   "/foo/a",
+		"through scalar":     "/foo/0/a",
+		"negative index":     "/foo/-1",
+		"missing nested key": "/foo/0/bar/baz",
+	}
+
+	for n, ptr := range tests {
+		t.Run(n, func(t *testing.T) {
+			ptr, err := ParsePointer(ptr)
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+
+			if _, err := ptr.Get(doc); err == nil {
+				t.Errorf("should have failed to get: %s", ptr)
+				return
+			}
+		})
+	}
+}
+
+func TestPointerUpdate(t *testing.T) {
+	newDoc := func() Value {
+		return NewObject(
+			Prop{key: "a", val: Array{Num{literal: "1"}, Num{literal: "2"}}},
+			Prop{key: "b", val: NewObject(Prop{key: "c", val: Bool(true)})},
+		)
+	}
+
+	tests := map[string]struct {
+		update   func(ptr Pointer, doc Value) (Value, error)
+		ptr      string
+		expected Value
+	}{
+		"set prop": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Set(doc, String("c"))
+			},
+			ptr: "/b/c",
+			expected: NewObject(
+				Prop{key: "a", val: Array{Num{literal: "1"}, Num{literal: "2"}}},
+				Prop{key: "b", val: NewObject(Prop{key: "c", val: String("c")})},
+			),
+		},
+		"set element": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Set(doc, Null{})
+			},
+			ptr: "/a/1",
+			expected: NewObject(
+				Prop{key: "a", val: Array{Num{literal: "1"}, Null{}}},
+				Prop{key: "b", val: NewObject(Prop{key: "c", val: Bool(true)})},
+			),
+		},
+		"set whole document": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Set(doc, Null{})
+			},
+			ptr:      "",
+			expected: Null{},
+		},
+		"add prop": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Add(doc, String("d"))
+			},
+			ptr: "/b/d",
+			expected: NewObject(
+				Prop{key: "a", val: Array{Num{literal: "1"}, Num{literal: "2"}}},
+				Prop{key: "b", val: NewObject(Prop{key: "c", val: Bool(true)}, Prop{key: "d", val: String("d")})},
+			),
+		},
+		"add existing prop": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Add(doc, String("c"))
+			},
+			ptr: "/b/c",
+			expected: NewObject(
+				Prop{key: "a", val: Array{Num{literal: "1"}, Num{literal: "2"}}},
+				Prop{key: "b", val: NewObject(Prop{key: "c", val: String("c")})},
+			),
+		},
+		"insert element": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Add(doc, Num{literal: "0"})
+			},
+			ptr: "/a/0",
+			expected: NewObject(
+				Prop{key: "a", val: Array{Num{literal: "0"}, Num{literal: "1"}, Num{literal: "2"}}},
+				Prop{key: "b", val: NewObject(Prop{key: "c", val: Bool(true)})},
+			),
+		},
+		"append element": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Add(doc, Num{literal: "3"})
+			},
+			ptr: "/a/-",
+			expected: NewObject(
+				Prop{key: "a", val: Array{Num{literal: "1"}, Num{literal: "2"}, Num{literal: "3"}}},
+				Prop{key: "b", val: NewObject(Prop{key: "c", val: Bool(true)})},
+			),
+		},
+		"append element by length": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Add(doc, Num{literal: "3"})
+			},
+			ptr: "/a/2",
+			expected: NewObject(
+				Prop{key: "a", val: Array{Num{literal: "1"}, Num{literal: "2"}, Num{literal: "3"}}},
+				Prop{key: "b", val: NewObject(Prop{key: "c", val: Bool(true)})},
+			),
+		},
+		"remove prop": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Remove(doc)
+			},
+			ptr: "/b/c",
+			expected: NewObject(
+				Prop{key: "a", val: Array{Num{literal: "1"}, Num{literal: "2"}}},
+				Prop{key: "b", val: NewObject()},
+			),
+		},
+		"remove element": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Remove(doc)
+			},
+			ptr: "/a/0",
+			expected: NewObject(
+				Prop{key: "a", val: Array{Num{literal: "2"}}},
+				Prop{key: "b", val: NewObject(Prop{key: "c", val: Bool(true)})},
+			),
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			ptr, err := ParsePointer(test.ptr)
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+
+			doc := newDoc()
+			actual, err := test.update(ptr, doc)
+			if err != nil {
+				t.Errorf("should have updated: %s", err)
+				return
+			}
+			if err := assertValue(actual, test.expected); err != nil {
+				t.Errorf("should have updated: %s", err)
+				return
+			}
+			if err := assertValue(doc, newDoc()); err != nil {
+				t.Errorf("should not have modified the given value: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestPointerUpdateFails(t *testing.T) {
+	doc := NewObject(
+		Prop{key: "a", val: Array{Num{literal: "1"}}},
+	)
+
+	tests := map[string]struct {
+		update func(ptr Pointer, doc Value) (Value, error)
+		ptr    string
+	}{
+		"set missing prop": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Set(doc, Null{})
+			},
+			ptr: "/b",
+		},
+		"set end of array": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Set(doc, Null{})
+			},
+			ptr: "/a/-",
+		},
+		"add out of range": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Add(doc, Null{})
+			},
+			ptr: "/a/2",
+		},
+		"add through missing prop": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Add(doc, Null{})
+			},
+			ptr: "/b/c",
+		},
+		"add into scalar": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Add(doc, Null{})
+			},
+			ptr: "/a/0/b",
+		},
+		"remove out of range": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Remove(doc)
+			},
+			ptr: "/a/1",
+		},
+		"remove whole document": {
+			update: func(ptr Pointer, doc Value) (Value, error) {
+				return ptr.Remove(doc)
+			},
+			ptr: "",
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			ptr, err := ParsePointer(test.ptr)
+			if err != nil {
+				t.Errorf("should have parsed: %s", err)
+				return
+			}
+
+			if _, err := test.update(ptr, doc); err == nil {
+				t.Errorf("should have failed to update: %s", ptr)
+				return
+			}
+		})
+	}
+}
+
+func assertPointer(actual, expected Pointer) error {
+	if len(actual) != len(expected) {
+		return reportUnexpected("len of pointer", len(actual), len(expected))
+	}
+	for i, expected := range expected {
+		if actual[i] != expected {
+			return reportUnexpected("token", actual[i], expected)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,971 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a compiled JSON Schema which validates JSON values.
+//
+// It supports the validation vocabulary of draft 2020-12 and the subset of the core and applicator vocabularies:
+// type, enum, const, required, dependentRequired, pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum,
+// multipleOf, minLength, maxLength, minItems, maxItems, uniqueItems, minContains, maxContains,
+// minProperties, maxProperties, properties, patternProperties, additionalProperties, prefixItems, items, contains,
+// allOf, anyOf, oneOf, not, $defs and $ref which references the schema in the same document.
+// The schema which has the other keywords of the applicator and unevaluated vocabularies or $dynamicRef
+// fails to compile as they would change the results, while the other keywords such as title are ignored
+// as they only annotate the values.
+type Schema struct {
+	root *schemaNode
+}
+
+// CompileSchema parses the given source, which should have only one value, as JSON Schema and compiles it.
+func CompileSchema(src []byte) (*Schema, error) {
+	val, err := ParserOptions{DisallowTrailingData: true}.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return CompileSchemaValue(val)
+}
+
+// CompileSchemaValue compiles the given value as JSON Schema.
+// It fails for the schema which applies itself to the same value endlessly such as {"$ref": "#"}.
+func CompileSchemaValue(val Value) (*Schema, error) {
+	c := schemaCompiler{
+		root:  val,
+		nodes: make(map[string]*schemaNode),
+	}
+
+	root, err := c.compile(val, Pointer{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema: %w", err)
+	}
+	if err := c.checkRefCycles(); err != nil {
+		return nil, fmt.Errorf("failed to compile schema: %w", err)
+	}
+
+	return &Schema{
+		root: root,
+	}, nil
+}
+
+// Validate parses the given source, which should have only one value, and validates the result.
+// It returns ValidationError whose violations are located in the source if the result is invalid.
+func (s *Schema) Validate(src []byte) error {
+	p := ParserOptions{DisallowTrailingData: true}.newParser(bytes.NewReader(src))
+	p.positions = make(map[string]pos)
+
+	val, err := p.parseDocument()
+	if err != nil {
+		return err
+	}
+
+	return s.validate(val, p.positions)
+}
+
+// ValidateValue validates the given value.
+// It returns ValidationError if the value is invalid.
+func (s *Schema) ValidateValue(val Value) error {
+	return s.validate(val, nil)
+}
+
+func (s *Schema) validate(val Value, positions map[string]pos) error {
+	v := validation{
+		positions: positions,
+	}
+	if v.validate(s.root, val, nil, Pointer{}) {
+		return nil
+	}
+
+	return &ValidationError{
+		Violations: v.violations,
+	}
+}
+
+// ValidationError describes the violations of a value against a schema.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to validate: %d violations", len(e.Violations))
+	for _, v := range e.Violations {
+		b.WriteString("\n\t")
+		b.WriteString(v.String())
+	}
+
+	return b.String()
+}
+
+// Violation describes where and why a value is invalid against a schema.
+type Violation struct {
+	// InstancePath is the path to the invalid value such as $.items[0].id.
+	InstancePath string
+	// SchemaPath is the pointer to the violated keyword along the way of the evaluation
+	// such as /properties/items/items/type, which goes through $ref.
+	SchemaPath string
+	// Line, Column and Offset locate the invalid value in the same way as SyntaxError.
+	// They are zero if the value is not parsed from source.
+	Line, Column int
+	Offset       int
+	Msg          string
+}
+
+func (v Violation) String() string {
+	var b strings.Builder
+	b.WriteString(v.InstancePath)
+	if v.Line != 0 {
+		fmt.Fprintf(&b, " at line %d, column %d", v.Line, v.Column)
+	}
+	fmt.Fprintf(&b, ": %s (%s)", v.Msg, v.SchemaPath)
+
+	return b.String()
+}
+
+// schemaNode is a compiled schema or subschema.
+type schemaNode struct {
+	// always is the result of the boolean schema if it is not nil.
+	always *bool
+
+	ref *schemaNode
+
+	types    []string
+	enum     []Value
+	constVal Value
+
+	properties           []schemaProp
+	patternProperties    []schemaPatternProp
+	additionalProperties *schemaNode
+	required             []string
+	dependentRequired    []schemaDependentRequired
+	minProperties        *int
+	maxProperties        *int
+
+	prefixItems []*schemaNode
+	items       *schemaNode
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+	contains    *schemaNode
+	minContains *int
+	maxContains *int
+
+	pattern   *regexp.Regexp
+	minLength *int
+	maxLength *int
+
+	minimum          *Num
+	maximum          *Num
+	exclusiveMinimum *Num
+	exclusiveMaximum *Num
+	multipleOf       *big.Rat
+
+	allOf []*schemaNode
+	anyOf []*schemaNode
+	oneOf []*schemaNode
+	not   *schemaNode
+}
+
+type schemaProp struct {
+	name string
+	node *schemaNode
+}
+
+type schemaDependentRequired struct {
+	name     string
+	required []string
+}
+
+type schemaPatternProp struct {
+	pattern *regexp.Regexp
+	node    *schemaNode
+}
+
+type schemaCompiler struct {
+	root Value
+	// nodes caches the compiled schemas by their locations so that recursive references terminate.
+	nodes map[string]*schemaNode
+}
+
+func (c *schemaCompiler) compile(val Value, at Pointer) (*schemaNode, error) {
+	if node, ok := c.nodes[at.String()]; ok {
+		return node, nil
+	}
+
+	node := &schemaNode{}
+	c.nodes[at.String()] = node
+
+	switch val := val.(type) {
+	case Bool:
+		always := bool(val)
+		node.always = &always
+		return node, nil
+	case Object:
+		if err := c.compileKeywords(node, val, at); err != nil {
+			return nil, err
+		}
+		return node, nil
+	default:
+		return nil, fmt.Errorf("schema at %q should be object or bool, but %s", at, kindOf(val))
+	}
+}
+
+func (c *schemaCompiler) compileKeywords(node *schemaNode, obj Object, at Pointer) error {
+	for _, prop := range obj.effectiveProps() {
+		keyword := string(prop.key)
+
+		if err := c.compileKeyword(node, keyword, prop.val, at.appended(keyword)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *schemaCompiler) compileKeyword(node *schemaNode, keyword string, val Value, at Pointer) error {
+	var err error
+	switch keyword {
+	case "$ref":
+		node.ref, err = c.compileRef(val, at)
+	case "type":
+		node.types, err = compileTypes(val, at)
+	case "enum":
+		arr, ok := val.(Array)
+		if !ok {
+			return fmt.Errorf("%q should be array", at)
+		}
+		node.enum = arr
+	case "const":
+		node.constVal = val
+	case "properties":
+		node.properties, err = c.compileProperties(val, at)
+	case "patternProperties":
+		node.patternProperties, err = c.compilePatternProperties(val, at)
+	case "additionalProperties":
+		node.additionalProperties, err = c.compile(val, at)
+	case "required":
+		node.required, err = compileStrings(val, at)
+	case "dependentRequired":
+		node.dependentRequired, err = compileDependentRequired(val, at)
+	case "minProperties":
+		node.minProperties, err = compileCount(val, at)
+	case "maxProperties":
+		node.maxProperties, err = compileCount(val, at)
+	case "prefixItems":
+		node.prefixItems, err = c.compileSchemas(val, at)
+	case "items":
+		node.items, err = c.compile(val, at)
+	case "minItems":
+		node.minItems, err = compileCount(val, at)
+	case "maxItems":
+		node.maxItems, err = compileCount(val, at)
+	case "uniqueItems":
+		b, ok := val.(Bool)
+		if !ok {
+			return fmt.Errorf("%q should be bool", at)
+		}
+		node.uniqueItems = bool(b)
+	case "contains":
+		node.contains, err = c.compile(val, at)
+	case "minContains":
+		node.minContains, err = compileCount(val, at)
+	case "maxContains":
+		node.maxContains, err = compileCount(val, at)
+	case "pattern":
+		node.pattern, err = compilePattern(val, at)
+	case "minLength":
+		node.minLength, err = compileCount(val, at)
+	case "maxLength":
+		node.maxLength, err = compileCount(val, at)
+	case "minimum":
+		node.minimum, err = compileNum(val, at)
+	case "maximum":
+		node.maximum, err = compileNum(val, at)
+	case "exclusiveMinimum":
+		node.exclusiveMinimum, err = compileNum(val, at)
+	case "exclusiveMaximum":
+		node.exclusiveMaximum, err = compileNum(val, at)
+	case "multipleOf":
+		node.multipleOf, err = compileMultipleOf(val, at)
+	case "allOf":
+		node.allOf, err = c.compileSchemas(val, at)
+	case "anyOf":
+		node.anyOf, err = c.compileSchemas(val, at)
+	case "oneOf":
+		node.oneOf, err = c.compileSchemas(val, at)
+	case "not":
+		node.not, err = c.compile(val, at)
+	case "$defs":
+		// The definitions are compiled when they are referenced.
+		_, ok := val.(Object)
+		if !ok {
+			return fmt.Errorf("%q should be object", at)
+		}
+	default:
+		if unsupportedSchemaKeywords[keyword] {
+			return fmt.Errorf("%q is not supported", at)
+		}
+	}
+
+	return err
+}
+
+// unsupportedSchemaKeywords are the keywords which affect the results of validation but are not supported.
+var unsupportedSchemaKeywords = map[string]bool{
+	"dependentSchemas": true, "propertyNames": true, "if": true, "then": true, "else": true,
+	"unevaluatedItems": true, "unevaluatedProperties": true, "$dynamicRef": true,
+}
+
+// checkRefCycles returns an error if any of the compiled schemas applies itself to the same value endlessly
+// through $ref and the applicators such as allOf, which apply their schemas to the same value as $ref does.
+// The cycles which go through the keywords for the props or the elements are fine as they go into the value.
+func (c *schemaCompiler) checkRefCycles() error {
+	locations := make([]string, 0, len(c.nodes))
+	for at := range c.nodes {
+		locations = append(locations, at)
+	}
+	sort.Strings(locations)
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	states := make(map[*schemaNode]int, len(c.nodes))
+	var check func(node *schemaNode) error
+	check = func(node *schemaNode) error {
+		switch states[node] {
+		case visiting:
+			return fmt.Errorf("%q references itself without going into the value", c.locationOf(node))
+		case visited:
+			return nil
+		}
+
+		states[node] = visiting
+		for _, applied := range node.appliedInPlace() {
+			if err := check(applied); err != nil {
+				return err
+			}
+		}
+		states[node] = visited
+
+		return nil
+	}
+
+	for _, at := range locations {
+		if err := check(c.nodes[at]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *schemaCompiler) locationOf(node *schemaNode) string {
+	for at, n := range c.nodes {
+		if n == node {
+			return at
+		}
+	}
+
+	return ""
+}
+
+// appliedInPlace returns the schemas which are applied to the same value as the node is.
+func (n *schemaNode) appliedInPlace() []*schemaNode {
+	var applied []*schemaNode
+	if n.ref != nil {
+		applied = append(applied, n.ref)
+	}
+	applied = append(applied, n.allOf...)
+	applied = append(applied, n.anyOf...)
+	applied = append(applied, n.oneOf...)
+	if n.not != nil {
+		applied = append(applied, n.not)
+	}
+
+	return applied
+}
+
+// compileRef compiles the schema which the given reference such as #/$defs/item references in the same document.
+func (c *schemaCompiler) compileRef(val Value, at Pointer) (*schemaNode, error) {
+	ref, ok := val.(String)
+	if !ok {
+		return nil, fmt.Errorf("%q should be string", at)
+	}
+	if !strings.HasPrefix(string(ref), "#") {
+		return nil, fmt.Errorf("%q references %s: only the references in the same document are supported", at, ref)
+	}
+
+	fragment, err := url.PathUnescape(string(ref[1:]))
+	if err != nil {
+		return nil, fmt.Errorf("%q has invalid reference %s: %w", at, ref, err)
+	}
+	ptr, err := ParsePointer(fragment)
+	if err != nil {
+		return nil, fmt.Errorf("%q has invalid reference %s: %w", at, ref, err)
+	}
+
+	target, err := ptr.Get(c.root)
+	if err != nil {
+		return nil, fmt.Errorf("%q references %s which is not found: %w", at, ref, err)
+	}
+
+	return c.compile(target, ptr)
+}
+
+func (c *schemaCompiler) compileProperties(val Value, at Pointer) ([]schemaProp, error) {
+	obj, ok := val.(Object)
+	if !ok {
+		return nil, fmt.Errorf("%q should be object", at)
+	}
+
+	props := make([]schemaProp, 0, obj.Len())
+	for _, prop := range obj.Props() {
+		name := string(prop.key)
+		node, err := c.compile(prop.val, at.appended(name))
+		if err != nil {
+			return nil, err
+		}
+
+		props = append(props, schemaProp{
+			name: name,
+			node: node,
+		})
+	}
+
+	return props, nil
+}
+
+func (c *schemaCompiler) compilePatternProperties(val Value, at Pointer) ([]schemaPatternProp, error) {
+	obj, ok := val.(Object)
+	if !ok {
+		return nil, fmt.Errorf("%q should be object", at)
+	}
+
+	props := make([]schemaPatternProp, 0, obj.Len())
+	for _, prop := range obj.Props() {
+		propAt := at.appended(string(prop.key))
+		pattern, err := regexp.Compile(string(prop.key))
+		if err != nil {
+			return nil, fmt.Errorf("%q has invalid pattern: %w", propAt, err)
+		}
+		node, err := c.compile(prop.val, propAt)
+		if err != nil {
+			return nil, err
+		}
+
+		props = append(props, schemaPatternProp{
+			pattern: pattern,
+			node:    node,
+		})
+	}
+
+	return props, nil
+}
+
+func (c *schemaCompiler) compileSchemas(val Value, at Pointer) ([]*schemaNode, error) {
+	arr, ok := val.(Array)
+	if !ok || len(arr) == 0 {
+		return nil, fmt.Errorf("%q should be non empty array", at)
+	}
+
+	nodes := make([]*schemaNode, len(arr))
+	for i, elem := range arr {
+		node, err := c.compile(elem, at.appended(fmt.Sprint(i)))
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = node
+	}
+
+	return nodes, nil
+}
+
+var schemaTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true, "number": true, "string": true, "integer": true,
+}
+
+func compileTypes(val Value, at Pointer) ([]string, error) {
+	if s, ok := val.(String); ok {
+		val = Array{s}
+	}
+
+	types, err := compileStrings(val, at)
+	if err != nil {
+		return nil, err
+	}
+	for _, typ := range types {
+		if !schemaTypes[typ] {
+			return nil, fmt.Errorf("%q has unknown type %s", at, typ)
+		}
+	}
+
+	return types, nil
+}
+
+func compileStrings(val Value, at Pointer) ([]string, error) {
+	arr, ok := val.(Array)
+	if !ok {
+		return nil, fmt.Errorf("%q should be array", at)
+	}
+
+	strs := make([]string, len(arr))
+	for i, elem := range arr {
+		s, ok := elem.(String)
+		if !ok {
+			return nil, fmt.Errorf("%q should have only strings", at)
+		}
+		strs[i] = string(s)
+	}
+
+	return strs, nil
+}
+
+func compileDependentRequired(val Value, at Pointer) ([]schemaDependentRequired, error) {
+	obj, ok := val.(Object)
+	if !ok {
+		return nil, fmt.Errorf("%q should be object", at)
+	}
+
+	deps := make([]schemaDependentRequired, 0, obj.Len())
+	for _, prop := range obj.effectiveProps() {
+		name := string(prop.key)
+		required, err := compileStrings(prop.val, at.appended(name))
+		if err != nil {
+			return nil, err
+		}
+
+		deps = append(deps, schemaDependentRequired{
+			name:     name,
+			required: required,
+		})
+	}
+
+	return deps, nil
+}
+
+func compileCount(val Value, at Pointer) (*int, error) {
+	n, ok := val.(Num)
+	if !ok {
+		return nil, fmt.Errorf("%q should be non negative integer", at)
+	}
+
+	i, err := n.Int64()
+	if err != nil || i < 0 || int64(int(i)) != i {
+		return nil, fmt.Errorf("%q should be non negative integer", at)
+	}
+	count := int(i)
+
+	return &count, nil
+}
+
+func compileNum(val Value, at Pointer) (*Num, error) {
+	n, ok := val.(Num)
+	if !ok {
+		return nil, fmt.Errorf("%q should be number", at)
+	}
+
+	return &n, nil
+}
+
+func compileMultipleOf(val Value, at Pointer) (*big.Rat, error) {
+	n, ok := val.(Num)
+	if !ok {
+		return nil, fmt.Errorf("%q should be number", at)
+	}
+
+	r, err := n.bigRat()
+	if err != nil {
+		return nil, fmt.Errorf("%q has invalid number: %w", at, err)
+	}
+	if r.Sign() <= 0 {
+		return nil, fmt.Errorf("%q should be positive", at)
+	}
+
+	return r, nil
+}
+
+func compilePattern(val Value, at Pointer) (*regexp.Regexp, error) {
+	s, ok := val.(String)
+	if !ok {
+		return nil, fmt.Errorf("%q should be string", at)
+	}
+
+	re, err := regexp.Compile(string(s))
+	if err != nil {
+		return nil, fmt.Errorf("%q has invalid pattern: %w", at, err)
+	}
+
+	return re, nil
+}
+
+// validation collects the violations of a value against a schema.
+type validation struct {
+	positions  map[string]pos
+	violations []Violation
+}
+
+// validate validates the value at the given instance path against the node at the given schema path,
+// and reports whether the value is valid.
+func (v *validation) validate(node *schemaNode, val Value, inst valuePath, sch Pointer) bool {
+	if node.always != nil {
+		if !*node.always {
+			v.add(inst, sch, "no value is allowed")
+		}
+		return *node.always
+	}
+
+	valid := true
+	check := func(ok bool) {
+		valid = valid && ok
+	}
+
+	if node.ref != nil {
+		check(v.validate(node.ref, val, inst, sch.appended("$ref")))
+	}
+
+	check(v.validateGeneric(node, val, inst, sch))
+	switch val := val.(type) {
+	case Object:
+		check(v.validateObject(node, val, inst, sch))
+	case Array:
+		check(v.validateArray(node, val, inst, sch))
+	case String:
+		check(v.validateString(node, val, inst, sch))
+	case Num:
+		check(v.validateNum(node, val, inst, sch))
+	}
+	check(v.validateApplicators(node, val, inst, sch))
+
+	return valid
+}
+
+func (v *validation) validateGeneric(node *schemaNode, val Value, inst valuePath, sch Pointer) bool {
+	valid := true
+
+	if len(node.types) != 0 && !matchesAnyType(val, node.types) {
+		v.add(inst, sch.appended("type"), "should be %s, but %s", strings.Join(node.types, " or "), schemaTypeOf(val))
+		valid = false
+	}
+
+	if node.enum != nil {
+		found := false
+		for _, elem := range node.enum {
+			if equalValues(val, elem) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.add(inst, sch.appended("enum"), "should be one of %s", compactString(Array(node.enum)))
+			valid = false
+		}
+	}
+
+	if node.constVal != nil && !equalValues(val, node.constVal) {
+		v.add(inst, sch.appended("const"), "should be %s", compactString(node.constVal))
+		valid = false
+	}
+
+	return valid
+}
+
+func (v *validation) validateObject(node *schemaNode, obj Object, inst valuePath, sch Pointer) bool {
+	valid := true
+
+	for _, name := range node.required {
+		if !obj.Has(name) {
+			v.add(inst, sch.appended("required"), "should have prop %q", name)
+			valid = false
+		}
+	}
+	for _, dep := range node.dependentRequired {
+		if !obj.Has(dep.name) {
+			continue
+		}
+		for _, name := range dep.required {
+			if !obj.Has(name) {
+				v.add(inst, sch.appended("dependentRequired").appended(dep.name), "should have prop %q as it has prop %q", name, dep.name)
+				valid = false
+			}
+		}
+	}
+
+	count := 0
+	for _, prop := range obj.effectiveProps() {
+		key := string(prop.key)
+		count++
+
+		propInst := inst.appended(keyElem(key))
+		evaluated := false
+		for _, p := range node.properties {
+			if p.name != key {
+				continue
+			}
+			evaluated = true
+			if !v.validate(p.node, prop.val, propInst, sch.appended("properties").appended(p.name)) {
+				valid = false
+			}
+		}
+		for _, p := range node.patternProperties {
+			if !p.pattern.MatchString(key) {
+				continue
+			}
+			evaluated = true
+			if !v.validate(p.node, prop.val, propInst, sch.appended("patternProperties").appended(p.pattern.String())) {
+				valid = false
+			}
+		}
+
+		if !evaluated && node.additionalProperties != nil {
+			if !v.validate(node.additionalProperties, prop.val, propInst, sch.appended("additionalProperties")) {
+				valid = false
+			}
+		}
+	}
+
+	if node.minProperties != nil && count < *node.minProperties {
+		v.add(inst, sch.appended("minProperties"), "should have at least %d props", *node.minProperties)
+		valid = false
+	}
+	if node.maxProperties != nil && count > *node.maxProperties {
+		v.add(inst, sch.appended("maxProperties"), "should have at most %d props", *node.maxProperties)
+		valid = false
+	}
+
+	return valid
+}
+
+func (v *validation) validateArray(node *schemaNode, arr Array, inst valuePath, sch Pointer) bool {
+	valid := true
+
+	for i, elem := range arr {
+		elemInst := inst.appended(indexElem(i))
+		switch {
+		case i < len(node.prefixItems):
+			if !v.validate(node.prefixItems[i], elem, elemInst, sch.appended("prefixItems").appended(fmt.Sprint(i))) {
+				valid = false
+			}
+		case node.items != nil:
+			if !v.validate(node.items, elem, elemInst, sch.appended("items")) {
+				valid = false
+			}
+		}
+	}
+
+	if node.minItems != nil && len(arr) < *node.minItems {
+		v.add(inst, sch.appended("minItems"), "should have at least %d items", *node.minItems)
+		valid = false
+	}
+	if node.maxItems != nil && len(arr) > *node.maxItems {
+		v.add(inst, sch.appended("maxItems"), "should have at most %d items", *node.maxItems)
+		valid = false
+	}
+
+	if node.contains != nil && !v.validateContains(node, arr, inst, sch) {
+		valid = false
+	}
+
+	if node.uniqueItems {
+	unique:
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if equalValues(arr[i], arr[j]) {
+					v.add(inst, sch.appended("uniqueItems"), "should have unique items, but items %d and %d are equal", i, j)
+					valid = false
+					break unique
+				}
+			}
+		}
+	}
+
+	return valid
+}
+
+// validateContains validates the number of the items which are valid against contains,
+// which should be at least minContains, or 1 without it, and at most maxContains.
+func (v *validation) validateContains(node *schemaNode, arr Array, inst valuePath, sch Pointer) bool {
+	var count int
+	for i, elem := range arr {
+		sub := validation{}
+		if sub.validate(node.contains, elem, inst.appended(indexElem(i)), sch.appended("contains")) {
+			count++
+		}
+	}
+
+	switch {
+	case node.minContains == nil && count == 0:
+		v.add(inst, sch.appended("contains"), "should have an item which is valid against the schema")
+		return false
+	case node.minContains != nil && count < *node.minContains:
+		v.add(inst, sch.appended("minContains"), "should have at least %d items which are valid against contains, but %d", *node.minContains, count)
+		return false
+	case node.maxContains != nil && count > *node.maxContains:
+		v.add(inst, sch.appended("maxContains"), "should have at most %d items which are valid against contains, but %d", *node.maxContains, count)
+		return false
+	default:
+		return true
+	}
+}
+
+func (v *validation) validateString(node *schemaNode, s String, inst valuePath, sch Pointer) bool {
+	valid := true
+
+	n := utf8.RuneCountInString(string(s))
+	if node.minLength != nil && n < *node.minLength {
+		v.add(inst, sch.appended("minLength"), "should have at least %d characters", *node.minLength)
+		valid = false
+	}
+	if node.maxLength != nil && n > *node.maxLength {
+		v.add(inst, sch.appended("maxLength"), "should have at most %d characters", *node.maxLength)
+		valid = false
+	}
+	if node.pattern != nil && !node.pattern.MatchString(string(s)) {
+		v.add(inst, sch.appended("pattern"), "should match %q", node.pattern)
+		valid = false
+	}
+
+	return valid
+}
+
+func (v *validation) validateNum(node *schemaNode, n Num, inst valuePath, sch Pointer) bool {
+	valid := true
+
+	if node.minimum != nil && n.cmp(*node.minimum) < 0 {
+		v.add(inst, sch.appended("minimum"), "should be >= %s", node.minimum)
+		valid = false
+	}
+	if node.maximum != nil && n.cmp(*node.maximum) > 0 {
+		v.add(inst, sch.appended("maximum"), "should be <= %s", node.maximum)
+		valid = false
+	}
+	if node.exclusiveMinimum != nil && n.cmp(*node.exclusiveMinimum) <= 0 {
+		v.add(inst, sch.appended("exclusiveMinimum"), "should be > %s", node.exclusiveMinimum)
+		valid = false
+	}
+	if node.exclusiveMaximum != nil && n.cmp(*node.exclusiveMaximum) >= 0 {
+		v.add(inst, sch.appended("exclusiveMaximum"), "should be < %s", node.exclusiveMaximum)
+		valid = false
+	}
+	if node.multipleOf != nil && !isMultipleOf(n, node.multipleOf) {
+		v.add(inst, sch.appended("multipleOf"), "should be multiple of %s", node.multipleOf.RatString())
+		valid = false
+	}
+
+	return valid
+}
+
+func isMultipleOf(n Num, divisor *big.Rat) bool {
+	r, err := n.bigRat()
+	if err != nil {
+		return false
+	}
+
+	return r.Quo(r, divisor).IsInt()
+}
+
+func (v *validation) validateApplicators(node *schemaNode, val Value, inst valuePath, sch Pointer) bool {
+	valid := true
+
+	for i, sub := range node.allOf {
+		if !v.validate(sub, val, inst, sch.appended("allOf").appended(fmt.Sprint(i))) {
+			valid = false
+		}
+	}
+
+	if node.anyOf != nil {
+		matched := v.countValid(node.anyOf, val, inst, sch.appended("anyOf"))
+		if matched == 0 {
+			v.add(inst, sch.appended("anyOf"), "should be valid against any of the schemas")
+			valid = false
+		}
+	}
+
+	if node.oneOf != nil {
+		matched := v.countValid(node.oneOf, val, inst, sch.appended("oneOf"))
+		if matched != 1 {
+			v.add(inst, sch.appended("oneOf"), "should be valid against exactly one of the schemas, but %d", matched)
+			valid = false
+		}
+	}
+
+	if node.not != nil {
+		sub := validation{}
+		if sub.validate(node.not, val, inst, sch.appended("not")) {
+			v.add(inst, sch.appended("not"), "should not be valid against the schema")
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+// countValid returns the number of the schemas against which the value is valid
+// without reporting the violations against each of them.
+func (v *validation) countValid(nodes []*schemaNode, val Value, inst valuePath, sch Pointer) int {
+	var count int
+	for i, node := range nodes {
+		sub := validation{}
+		if sub.validate(node, val, inst, sch.appended(fmt.Sprint(i))) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (v *validation) add(inst valuePath, sch Pointer, format string, args ...interface{}) {
+	violation := Violation{
+		InstancePath: inst.String(),
+		SchemaPath:   sch.String(),
+		Msg:          fmt.Sprintf(format, args...),
+	}
+	if at, ok := v.positions[violation.InstancePath]; ok {
+		violation.Line, violation.Column, violation.Offset = at.line+1, at.start+1, at.offset
+	}
+
+	v.violations = append(v.violations, violation)
+}
+
+func matchesAnyType(val Value, types []string) bool {
+	actual := schemaTypeOf(val)
+	for _, typ := range types {
+		if typ == actual || typ == "number" && actual == "integer" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// schemaTypeOf returns the type of the value in JSON Schema,
+// where the numbers whose fractional parts are zero such as 1.0 are integers.
+func schemaTypeOf(val Value) string {
+	switch val := val.(type) {
+	case Null:
+		return "null"
+	case Bool:
+		return "boolean"
+	case Object:
+		return "object"
+	case Array:
+		return "array"
+	case String:
+		return "string"
+	case Num:
+		if r, err := val.bigRat(); err == nil && r.IsInt() {
+			return "integer"
+		}
+		return "number"
+	default:
+		return string(kindOf(val))
+	}
+}
@@ -0,0 +1,367 @@
+package json
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	tests := map[string]struct {
+		schema string
+		valid  []string
+		// invalid is the values which are invalid against the schema keyed by the expected schema path of the first violation.
+		invalid map[string]string
+	}{
+		"type": {
+			schema: `{"type": ["string", "null"]}`,
+			valid:  []string{`"a"`, `null`},
+			invalid: map[string]string{
+				"/type": `1`,
+			},
+		},
+		"integer": {
+			schema: `{"type": "integer"}`,
+			valid:  []string{`1`, `1.0`, `-2e3`},
+			invalid: map[string]string{
+				"/type": `1.5`,
+			},
+		},
+		"number": {
+			schema: `{"type": "number"}`,
+			valid:  []string{`1`, `1.5`},
+			invalid: map[string]string{
+				"/type": `"1"`,
+			},
+		},
+		"enum": {
+			schema: `{"enum": [1, "a", {"b": [true]}]}`,
+			valid:  []string{`1.0`, `"a"`, `{"b": [true]}`},
+			invalid: map[string]string{
+				"/enum": `{"b": [false]}`,
+			},
+		},
+		"const": {
+			schema: `{"const": {"a": 1, "b": 2}}`,
+			valid:  []string{`{"b": 2, "a": 1}`},
+			invalid: map[string]string{
+				"/const": `{"a": 1}`,
+			},
+		},
+		"boolean schema": {
+			schema: `{"properties": {"a": true, "b": false}}`,
+			valid:  []string{`{"a": 1}`},
+			invalid: map[string]string{
+				"/properties/b": `{"b": 1}`,
+			},
+		},
+		"dependent required": {
+			schema: `{"dependentRequired": {"a": ["b", "c"]}}`,
+			valid:  []string{`{"a": 1, "b": 2, "c": 3}`, `{"b": 2}`, `[]`},
+			invalid: map[string]string{
+				"/dependentRequired/a": `{"a": 1, "b": 2}`,
+			},
+		},
+		"contains": {
+			schema: `{"contains": {"type": "string"}}`,
+			valid:  []string{`[1, "a"]`, `{"a": 1}`},
+			invalid: map[string]string{
+				"/contains": `[1, 2]`,
+			},
+		},
+		"min and max contains": {
+			schema: `{"contains": {"type": "string"}, "minContains": 2, "maxContains": 3}`,
+			valid:  []string{`["a", 1, "b"]`, `["a", "b", "c"]`},
+			invalid: map[string]string{
+				"/minContains": `["a", 1]`,
+				"/maxContains": `["a", "b", "c", "d"]`,
+			},
+		},
+		"zero min contains": {
+			schema: `{"contains": {"type": "string"}, "minContains": 0}`,
+			valid:  []string{`[]`, `[1]`},
+		},
+		"properties": {
+			schema: `{"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}`,
+			valid:  []string{`{"name": "a"}`, `{"name": "a", "age": 1}`},
+			invalid: map[string]string{
+				"/required":             `{}`,
+				"/properties/name/type": `{"name": 1}`,
+				"/type":                 `[]`,
+			},
+		},
+		"additional properties": {
+			schema: `{"properties": {"a": {}}, "patternProperties": {"^x-": {"type": "string"}}, "additionalProperties": false}`,
+			valid:  []string{`{"a": 1, "x-b": "c"}`},
+			invalid: map[string]string{
+				"/patternProperties/^x-/type": `{"x-b": 1}`,
+				"/additionalProperties":       `{"b": 1}`,
+			},
+		},
+		"number of properties": {
+			schema: `{"minProperties": 1, "maxProperties": 2}`,
+			valid:  []string{`{"a": 1}`, `{"a": 1, "a": 2, "b": 3}`},
+			invalid: map[string]string{
+				"/minProperties": `{}`,
+				"/maxProperties": `{"a": 1, "b": 2, "c": 3}`,
+			},
+		},
+		"items": {
+			schema: `{"prefixItems": [{"type": "string"}], "items": {"type": "integer"}, "minItems": 1, "maxItems": 3}`,
+			valid:  []string{`["a"]`, `["a", 1, 2]`},
+			invalid: map[string]string{
+				"/prefixItems/0/type": `[1]`,
+				"/items/type":         `["a", "b"]`,
+				"/minItems":           `[]`,
+				"/maxItems":           `["a", 1, 2, 3]`,
+			},
+		},
+		"unique items": {
+			schema: `{"uniqueItems": true}`,
+			valid:  []string{`[1, "1", [1]]`},
+			invalid: map[string]string{
+				"/uniqueItems": `[{"a": 1}, {"a": 1.0}]`,
+			},
+		},
+		"string": {
+			schema: `{"minLength": 2, "maxLength": 3, "pattern": "^[a-zあ-ん]+$"}`,
+			valid:  []string{`"ab"`, `"あいう"`, `1`},
+			invalid: map[string]string{
+				"/minLength": `"a"`,
+				"/maxLength": `"abcd"`,
+				"/pattern":   `"AB"`,
+			},
+		},
+		"pattern without anchor": {
+			schema: `{"pattern": "b"}`,
+			valid:  []string{`"abc"`},
+			invalid: map[string]string{
+				"/pattern": `"ac"`,
+			},
+		},
+		"numeric bounds": {
+			schema: `{"minimum": 1, "exclusiveMaximum": 10.5}`,
+			valid:  []string{`1`, `10.49999999999999999999`, `"a"`},
+			invalid: map[string]string{
+				"/minimum":          `0.99999999999999999999`,
+				"/exclusiveMaximum": `10.5`,
+			},
+		},
+		"exclusive minimum and maximum": {
+			schema: `{"exclusiveMinimum": 0, "maximum": 1e2}`,
+			valid:  []string{`0.1`, `100`},
+			invalid: map[string]string{
+				"/exclusiveMinimum": `0`,
+				"/maximum":          `100.1`,
+			},
+		},
+		"multiple of": {
+			schema: `{"multipleOf": 0.1}`,
+			valid:  []string{`0.3`, `12`, `-1.5`},
+			invalid: map[string]string{
+				"/multipleOf": `0.35`,
+			},
+		},
+		"all of": {
+			schema: `{"allOf": [{"type": "integer"}, {"minimum": 2}]}`,
+			valid:  []string{`2`},
+			invalid: map[string]string{
+				"/allOf/0/type":    `2.5`,
+				"/allOf/1/minimum": `1`,
+			},
+		},
+		"any of": {
+			schema: `{"anyOf": [{"type": "string"}, {"minimum": 2}]}`,
+			valid:  []string{`"a"`, `3`},
+			invalid: map[string]string{
+				"/anyOf": `1`,
+			},
+		},
+		"one of": {
+			schema: `{"oneOf": [{"type": "integer"}, {"minimum": 2}]}`,
+			valid:  []string{`1`, `2.5`},
+			invalid: map[string]string{
+				"/oneOf": `3`,
+			},
+		},
+		"not": {
+			schema: `{"not": {"type": "null"}}`,
+			valid:  []string{`1`},
+			invalid: map[string]string{
+				"/not": `null`,
+			},
+		},
+		"ref": {
+			schema: `{"$defs": {"positive": {"exclusiveMinimum": 0}}, "properties": {"a": {"$ref": "#/$defs/positive"}}}`,
+			valid:  []string{`{"a": 1}`},
+			invalid: map[string]string{
+				"/properties/a/$ref/exclusiveMinimum": `{"a": 0}`,
+			},
+		},
+		"escaped ref": {
+			schema: `{"$defs": {"a b/c": {"type": "string"}}, "$ref": "#/$defs/a%20b~1c"}`,
+			valid:  []string{`"a"`},
+			invalid: map[string]string{
+				"/$ref/type": `1`,
+			},
+		},
+		"recursive ref": {
+			schema: `{"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#"}}}}`,
+			valid:  []string{`{"children": [{"children": []}, {}]}`},
+			invalid: map[string]string{
+				"/properties/children/items/$ref/properties/children/items/$ref/type": `{"children": [{"children": [1]}]}`,
+			},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			schema, err := CompileSchema([]byte(test.schema))
+			if err != nil {
+				t.Errorf("should have compiled the schema: %s", err)
+				return
+			}
+
+			for _, src := range test.valid {
+				if err := schema.Validate([]byte(src)); err != nil {
+					t.Errorf("should have validated %s: %s", src, err)
+					return
+				}
+			}
+
+			for expected, src := range test.invalid {
+				err := schema.Validate([]byte(src))
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Errorf("should have returned ValidationError for %s: %v", src, err)
+					return
+				}
+				if actual := validationErr.Violations[0].SchemaPath; actual != expected {
+					t.Errorf("should have reported the violated keyword for %s: %s", src, reportUnexpected("schema path", actual, expected))
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaValidateReportsViolations(t *testing.T) {
+	schema, err := CompileSchema([]byte(`{
+	"type": "object",
+	"properties": {
+		"id": {"type": "integer"},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	},
+	"required": ["id", "name"]
+}`))
+	if err != nil {
+		t.Errorf("should have compiled the schema: %s", err)
+		return
+	}
+
+	src := `{
+  "id": "1",
+  "tags": ["a", 2]
+}`
+	expected := []Violation{
+		{
+			InstancePath: "$", SchemaPath: "/required",
+			Line: 1, Column: 1, Offset: 0,
+			Msg: `should have prop "name"`,
+		},
+		{
+			InstancePath: "$.id", SchemaPath: "/properties/id/type",
+			Line: 2, Column: 9, Offset: 10,
+			Msg: "should be integer, but string",
+		},
+		{
+			InstancePath: "$.tags[1]", SchemaPath: "/properties/tags/items/type",
+			Line: 3, Column: 17, Offset: 31,
+			Msg: "should be string, but integer",
+		},
+	}
+
+	err = schema.Validate([]byte(src))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("should have returned ValidationError: %v", err)
+		return
+	}
+	if len(validationErr.Violations) != len(expected) {
+		t.Errorf("should have reported the violations: %s", reportUnexpected("len of violations", len(validationErr.Violations), len(expected)))
+		return
+	}
+	for i, actual := range validationErr.Violations {
+		if actual != expected[i] {
+			t.Errorf("should have reported the violation: %s", reportUnexpected("violation", actual, expected[i]))
+			return
+		}
+	}
+}
+
+func TestSchemaValidateTrailingData(t *testing.T) {
+	schema, err := CompileSchema([]byte(`{"type": "object"}`))
+	if err != nil {
+		t.Errorf("should have compiled the schema: %s", err)
+		return
+	}
+
+	err = schema.Validate([]byte(`{"a": 1} garbage`))
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("should have returned SyntaxError for the trailing data: %v", err)
+		return
+	}
+}
+
+func TestSchemaValidateValue(t *testing.T) {
+	schema, err := CompileSchema([]byte(`{"items": {"type": "boolean"}}`))
+	if err != nil {
+		t.Errorf("should have compiled the schema: %s", err)
+		return
+	}
+
+	err = schema.ValidateValue(Array{Bool(true), String("a")})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("should have returned ValidationError: %v", err)
+		return
+	}
+	expected := Violation{
+		InstancePath: "$[1]", SchemaPath: "/items/type",
+		Msg: "should be boolean, but string",
+	}
+	if actual := validationErr.Violations[0]; actual != expected {
+		t.Errorf("should have reported the violation without position: %s", reportUnexpected("violation", actual, expected))
+		return
+	}
+}
+
+func TestCompileInvalidSchema(t *testing.T) {
+	tests := map[string]string{
+		"not schema":         `1`,
+		"unknown type":       `{"type": "float"}`,
+		"invalid pattern":    `{"pattern": "("}`,
+		"negative length":    `{"minLength": -1}`,
+		"zero multiple":      `{"multipleOf": 0}`,
+		"empty all of":       `{"allOf": []}`,
+		"remote ref":         `{"$ref": "https://example.com/schema"}`,
+		"missing ref":        `{"$ref": "#/$defs/a"}`,
+		"self ref":           `{"$ref": "#"}`,
+		"ref cycle":          `{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"allOf": [{"$ref": "#/$defs/a"}]}}, "$ref": "#/$defs/a"}`,
+		"invalid subschema":  `{"properties": {"a": "string"}}`,
+		"trailing data":      `{"type": "string"} garbage`,
+		"invalid dependency": `{"dependentRequired": {"a": "b"}}`,
+		"negative contains":  `{"contains": true, "minContains": -1}`,
+		"unsupported if":     `{"if": {"type": "string"}, "then": {"minLength": 1}}`,
+		"unsupported nested": `{"items": {"unevaluatedProperties": false}}`,
+	}
+
+	for n, src := range tests {
+		t.Run(n, func(t *testing.T) {
+			if _, err := CompileSchema([]byte(src)); err == nil {
+				t.Errorf("should have failed to compile: %s", src)
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package json
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+func unquoteStringLiteral(lit string, at pos) (string, *SyntaxError) {
+	return unquoteDialectStringLiteral(lit, at, DialectJSON)
+}
+
+// unquoteDialectStringLiteral unquotes the given literal of string of the dialect.
+// Only JSON5 differs from JSON, which allows strings to be quoted by single quotes, to contain control characters
+// but line terminators, and to have the escape sequences such as \x41 and line continuations.
+func unquoteDialectStringLiteral(lit string, at pos, dialect Dialect) (string, *SyntaxError) {
+	json5 := dialect == DialectJSON5
+	if !isStringLiteralQuoted(lit) && !(json5 && isStringLiteralSingleQuoted(lit)) {
+		if json5 {
+			return "", newSyntaxError(at, "invalid string format: string should be quoted by '\"' or \"'\"")
+		}
+		return "", newSyntaxError(at, "invalid string format: string should be quoted by '\"'")
+	}
+
+	src := []rune(lit[1 : len(lit)-1])
+	// posOf returns the pos of the i-th character in src.
+	// + 1 is for the opening quotation.
+	posOf := func(i int) pos {
+		return at.shift(i+1, len(string(src[:i]))+1)
+	}
+
+	var b strings.Builder
+	b.Grow(len(src))
+
+	for i := 0; i < len(src); i++ {
+		start := i
+
+		c := src[i]
+		if json5 && (c == '\n' || c == '\r') {
+			return "", newSyntaxError(posOf(start), "invalid string format: string should not contain line terminator %U", c)
+		}
+		if !json5 && isControlChar(c) {
+			return "", newSyntaxError(posOf(start), "invalid string format: string should not contain control character %U", c)
+		}
+		if c != '\\' {
+			b.WriteRune(c)
+			continue
+		}
+
+		i++
+		if i >= len(src) {
+			return "", newSyntaxError(posOf(start), "invalid string format: incomplete escape sequence")
+		}
+
+		switch src[i] {
+		case '"', '\\', '/':
+			b.WriteRune(src[i])
+		case 'b':
+			b.WriteRune('\b')
+		case 'f':
+			b.WriteRune('\f')
+		case 'n':
+			b.WriteRune('\n')
+		case 'r':
+			b.WriteRune('\r')
+		case 't':
+			b.WriteRune('\t')
+		case 'u':
+			r, n, err := decodeUnicodeEscape(src[i+1:])
+			if err != nil {
+				return "", newSyntaxError(posOf(start), "invalid string format: invalid unicode escape: %s", err)
+			}
+			i += n
+
+			if !utf16.IsSurrogate(r) {
+				b.WriteRune(r)
+				continue
+			}
+			if r >= 0xdc00 {
+				return "", newSyntaxError(posOf(start), "invalid string format: lone low surrogate %U", r)
+			}
+
+			rest := src[i+1:]
+			if len(rest) < 2 || rest[0] != '\\' || rest[1] != 'u' {
+				return "", newSyntaxError(posOf(start), "invalid string format: lone high surrogate %U", r)
+			}
+			low, n, err := decodeUnicodeEscape(rest[2:])
+			if err != nil {
+				return "", newSyntaxError(posOf(i+1), "invalid string format: invalid unicode escape: %s", err)
+			}
+			decoded := utf16.DecodeRune(r, low)
+			if decoded == unicode.ReplacementChar {
+				return "", newSyntaxError(posOf(start), "invalid string format: high surrogate %U is not followed by low surrogate", r)
+			}
+			i += 2 + n
+
+			b.WriteRune(decoded)
+		default:
+			if !json5 {
+				return "", newSyntaxError(posOf(start), "invalid string format: invalid escape sequence '\\%c'", src[i])
+			}
+
+			n, err := unescapeJSON5(&b, src[i:])
+			if err != nil {
+				return "", newSyntaxError(posOf(start), "invalid string format: %s", err)
+			}
+			i += n
+		}
+	}
+
+	return b.String(), nil
+}
+
+// unescapeJSON5 writes the character which the escape sequence of JSON5 without the leading '\' represents,
+// which is not the one of JSON, and returns the number of the characters of the sequence after the first one.
+func unescapeJSON5(b *strings.Builder, src []rune) (int, error) {
+	switch c := src[0]; {
+	case c == '\'':
+		b.WriteRune(c)
+	case c == 'v':
+		b.WriteRune('\v')
+	case c == '0':
+		if len(src) > 1 && isNum(src[1]) {
+			return 0, fmt.Errorf("invalid escape sequence '\\0%c'", src[1])
+		}
+		b.WriteRune(0)
+	case c == 'x':
+		const digits = 2
+		if len(src) < 1+digits {
+			return 0, fmt.Errorf("\\x should be followed by %d hex digits", digits)
+		}
+		var r rune
+		for _, c := range src[1 : 1+digits] {
+			d, ok := hexDigit(c)
+			if !ok {
+				return 0, fmt.Errorf("invalid hex digit '%c'", c)
+			}
+			r = r<<4 | d
+		}
+		b.WriteRune(r)
+		return digits, nil
+	case c == '\r':
+		// The line continuation is removed including \r\n.
+		if len(src) > 1 && src[1] == '\n' {
+			return 1, nil
+		}
+	case c == '\n', c == '\u2028', c == '\u2029':
+	case isNum(c):
+		return 0, fmt.Errorf("invalid escape sequence '\\%c'", c)
+	default:
+		b.WriteRune(c)
+	}
+
+	return 0, nil
+}
+
+func decodeUnicodeEscape(src []rune) (rune, int, error) {
+	const digits = 4
+	if len(src) < digits {
+		return 0, 0, fmt.Errorf("\\u should be followed by %d hex digits", digits)
+	}
+
+	var r rune
+	for _, c := range src[:digits] {
+		d, ok := hexDigit(c)
+		if !ok {
+			return 0, 0, fmt.Errorf("invalid hex digit '%c'", c)
+		}
+		r = r<<4 | d
+	}
+
+	return r, digits, nil
+}
+
+func hexDigit(c rune) (rune, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	default:
+		return 0, false
+	}
+}
+
+func isStringLiteralQuoted(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+
+	return s[0] == '"' && s[len(s)-1] == '"'
+}
+
+func isStringLiteralSingleQuoted(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+
+	return s[0] == '\'' && s[len(s)-1] == '\''
+}
+
+func isControlChar(c rune) bool {
+	return c < 0x20
+}
+
+const hexDigits = "0123456789abcdef"
+
+// quoteString quotes the given string escaping characters which RFC 8259 requires to be escaped.
+// It also escapes '<', '>' and '&' if html is true, and non ASCII characters if ascii is true.
+func quoteString(s string, html, ascii bool) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+
+	b.WriteByte('"')
+	for _, c := range s {
+		switch c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '<', '>', '&':
+			if html {
+				writeUnicodeEscape(&b, c)
+				continue
+			}
+			b.WriteRune(c)
+		// U+2028 and U+2029 are valid in JSON but not in JavaScript.
+		case '\u2028', '\u2029':
+			if html {
+				writeUnicodeEscape(&b, c)
+				continue
+			}
+			b.WriteRune(c)
+		default:
+			switch {
+			case isControlChar(c):
+				writeUnicodeEscape(&b, c)
+			case ascii && c > unicode.MaxASCII:
+				if r1, r2 := utf16.EncodeRune(c); r1 != unicode.ReplacementChar {
+					writeUnicodeEscape(&b, r1)
+					writeUnicodeEscape(&b, r2)
+					continue
+				}
+				writeUnicodeEscape(&b, c)
+			default:
+				b.WriteRune(c)
+			}
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+func writeUnicodeEscape(b *strings.Builder, c rune) {
+	b.WriteString(`\u`)
+	for shift := 12; shift >= 0; shift -= 4 {
+		b.WriteByte(hexDigits[c>>uint(shift)&0xf])
+	}
+}
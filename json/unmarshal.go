@@ -0,0 +1,525 @@
+package json
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Unmarshaler is implemented by types which unmarshal JSON values into themselves.
+type Unmarshaler interface {
+	UnmarshalJSONValue(Value) error
+}
+
+// Unmarshal parses the given source and stores the result into the value pointed by v.
+// It is the same as UnmarshalOptions{}.Unmarshal(src, v).
+func Unmarshal(src []byte, v interface{}) error {
+	return UnmarshalOptions{}.Unmarshal(src, v)
+}
+
+// UnmarshalValue stores the given JSON value into the Go value pointed by v.
+// It is the same as UnmarshalOptions{}.UnmarshalValue(val, v).
+func UnmarshalValue(val Value, v interface{}) error {
+	return UnmarshalOptions{}.UnmarshalValue(val, v)
+}
+
+// UnmarshalOptions configures how JSON values are stored into Go values.
+//
+// JSON values are stored into Go values as follows.
+//   - null sets nil to pointers, interfaces, maps and slices, and leaves the others unchanged.
+//   - Objects are stored into structs and maps whose keys are strings, integers or encoding.TextUnmarshaler.
+//   - Arrays are stored into slices and arrays, and strings are stored into []byte as base64 as well.
+//   - Numbers are stored into integers only if they are integers such as 1.0, and into unsigned integers only if they are not negative.
+//   - Values are stored into interface{} as nil, bool, Num, string, []interface{} and map[string]interface{}.
+//   - Values are stored as they are into Value and the types which implement it.
+//
+// Struct fields are mapped onto props in the same way as encoding/json including
+// the tag such as `json:"name,omitempty,string"`.
+type UnmarshalOptions struct {
+	// DisallowUnknownFields makes it an error that objects have props
+	// which are not mapped onto any fields of the structs.
+	DisallowUnknownFields bool
+}
+
+// Unmarshal parses the given source, which should have only one value, and stores the result into v.
+func (o UnmarshalOptions) Unmarshal(src []byte, v interface{}) error {
+	p := ParserOptions{DisallowTrailingData: true}.newParser(bytes.NewReader(src))
+	p.positions = make(map[string]pos)
+
+	val, err := p.parseDocument()
+	if err != nil {
+		return err
+	}
+
+	return o.unmarshal(val, v, p.positions)
+}
+
+func (o UnmarshalOptions) UnmarshalValue(val Value, v interface{}) error {
+	return o.unmarshal(val, v, nil)
+}
+
+func (o UnmarshalOptions) unmarshal(val Value, v interface{}, positions map[string]pos) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("failed to unmarshal: non nil pointer should be given: %T", v)
+	}
+
+	u := unmarshaler{
+		opts:      o,
+		positions: positions,
+	}
+
+	return u.unmarshal(val, rv.Elem(), nil)
+}
+
+type unmarshaler struct {
+	opts      UnmarshalOptions
+	positions map[string]pos
+}
+
+var (
+	valueType           = reflect.TypeOf((*Value)(nil)).Elem()
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func (u unmarshaler) unmarshal(val Value, rv reflect.Value, path valuePath) error {
+	if val == nil {
+		val = Null{}
+	}
+
+	if rv.CanAddr() && rv.Addr().Type().Implements(unmarshalerType) {
+		if err := rv.Addr().Interface().(Unmarshaler).UnmarshalJSONValue(val); err != nil {
+			return u.errorf(path, err, "%s failed to unmarshal", rv.Type())
+		}
+
+		return nil
+	}
+
+	_, isNull := val.(Null)
+	if rv.Kind() == reflect.Ptr {
+		if isNull {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+
+		return u.unmarshal(val, rv.Elem(), path)
+	}
+	if rv.Kind() == reflect.Interface && rv.NumMethod() == 0 {
+		if isNull {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+
+		rv.Set(reflect.ValueOf(toGo(val)))
+
+		return nil
+	}
+
+	if reflect.TypeOf(val).AssignableTo(rv.Type()) {
+		rv.Set(reflect.ValueOf(val))
+		return nil
+	}
+	if rv.Type().Implements(valueType) {
+		return u.mismatchError(val, rv, path)
+	}
+
+	if isNull {
+		switch rv.Kind() {
+		case reflect.Interface, reflect.Map, reflect.Slice:
+			rv.Set(reflect.Zero(rv.Type()))
+		}
+
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		b, ok := val.(Bool)
+		if !ok {
+			return u.mismatchError(val, rv, path)
+		}
+		rv.SetBool(bool(b))
+
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := val.(Num)
+		if !ok {
+			return u.mismatchError(val, rv, path)
+		}
+		i, err := n.Int64()
+		if err != nil || rv.OverflowInt(i) {
+			return u.integerError(n, rv, path)
+		}
+		rv.SetInt(i)
+
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := val.(Num)
+		if !ok {
+			return u.mismatchError(val, rv, path)
+		}
+		i, err := n.BigInt()
+		if err != nil || !i.IsUint64() || rv.OverflowUint(i.Uint64()) {
+			return u.integerError(n, rv, path)
+		}
+		rv.SetUint(i.Uint64())
+
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, ok := val.(Num)
+		if !ok {
+			return u.mismatchError(val, rv, path)
+		}
+		f, err := n.Float64()
+		if err != nil || rv.OverflowFloat(f) {
+			return u.errorf(path, nil, "%s overflows %s", n, rv.Type())
+		}
+		rv.SetFloat(f)
+
+		return nil
+	case reflect.String:
+		s, ok := val.(String)
+		if !ok {
+			return u.mismatchError(val, rv, path)
+		}
+		rv.SetString(string(s))
+
+		return nil
+	case reflect.Slice:
+		if s, ok := val.(String); ok && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return u.unmarshalBase64(s, rv, path)
+		}
+		arr, ok := val.(Array)
+		if !ok {
+			return u.mismatchError(val, rv, path)
+		}
+
+		return u.unmarshalSlice(arr, rv, path)
+	case reflect.Array:
+		arr, ok := val.(Array)
+		if !ok {
+			return u.mismatchError(val, rv, path)
+		}
+
+		return u.unmarshalArray(arr, rv, path)
+	case reflect.Map:
+		obj, ok := val.(Object)
+		if !ok {
+			return u.mismatchError(val, rv, path)
+		}
+
+		return u.unmarshalMap(obj, rv, path)
+	case reflect.Struct:
+		obj, ok := val.(Object)
+		if !ok {
+			return u.mismatchError(val, rv, path)
+		}
+
+		return u.unmarshalStruct(obj, rv, path)
+	default:
+		return u.errorf(path, nil, "unsupported type: %s", rv.Type())
+	}
+}
+
+// unmarshalBase64 stores the bytes which the string encodes in base64 into the slice of bytes.
+func (u unmarshaler) unmarshalBase64(s String, rv reflect.Value, path valuePath) error {
+	b, err := base64.StdEncoding.DecodeString(string(s))
+	if err != nil {
+		return u.errorf(path, err, "failed to decode base64")
+	}
+	rv.SetBytes(b)
+
+	return nil
+}
+
+func (u unmarshaler) unmarshalSlice(arr Array, rv reflect.Value, path valuePath) error {
+	slice := reflect.MakeSlice(rv.Type(), len(arr), len(arr))
+	for i, elem := range arr {
+		if err := u.unmarshal(elem, slice.Index(i), path.appended(indexElem(i))); err != nil {
+			return err
+		}
+	}
+
+	rv.Set(slice)
+
+	return nil
+}
+
+func (u unmarshaler) unmarshalArray(arr Array, rv reflect.Value, path valuePath) error {
+	for i := 0; i < rv.Len(); i++ {
+		if i >= len(arr) {
+			rv.Index(i).Set(reflect.Zero(rv.Type().Elem()))
+			continue
+		}
+		if err := u.unmarshal(arr[i], rv.Index(i), path.appended(indexElem(i))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u unmarshaler) unmarshalMap(obj Object, rv reflect.Value, path valuePath) error {
+	keyType := rv.Type().Key()
+	if !canBeMapKey(keyType) {
+		return u.errorf(path, nil, "unsupported type of map key: %s", keyType)
+	}
+
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(rv.Type(), obj.Len()))
+	}
+
+	for _, prop := range obj.Props() {
+		propPath := path.appended(keyElem(string(prop.key)))
+
+		key, err := u.mapKey(string(prop.key), keyType)
+		if err != nil {
+			return u.errorf(propPath, err, "failed to convert key into %s", keyType)
+		}
+
+		elem := reflect.New(rv.Type().Elem()).Elem()
+		if existing := rv.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := u.unmarshal(prop.val, elem, propPath); err != nil {
+			return err
+		}
+
+		rv.SetMapIndex(key, elem)
+	}
+
+	return nil
+}
+
+func canBeMapKey(t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+func (u unmarshaler) mapKey(key string, t reflect.Type) (reflect.Value, error) {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		rv := reflect.New(t)
+		if err := rv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+			return reflect.Value{}, err
+		}
+
+		return rv.Elem(), nil
+	}
+
+	rv := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		rv.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || rv.OverflowInt(i) {
+			return reflect.Value{}, fmt.Errorf("%q is not %s", key, t)
+		}
+		rv.SetInt(i)
+	default:
+		i, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || rv.OverflowUint(i) {
+			return reflect.Value{}, fmt.Errorf("%q is not %s", key, t)
+		}
+		rv.SetUint(i)
+	}
+
+	return rv, nil
+}
+
+func (u unmarshaler) unmarshalStruct(obj Object, rv reflect.Value, path valuePath) error {
+	fields := cachedStructFields(rv.Type())
+	for _, prop := range obj.Props() {
+		propPath := path.appended(keyElem(string(prop.key)))
+
+		f, ok := fields.lookup(string(prop.key))
+		if !ok {
+			if u.opts.DisallowUnknownFields {
+				return u.errorf(propPath, nil, "unknown field %q in %s", prop.key, rv.Type())
+			}
+			continue
+		}
+
+		frv, err := fieldByIndex(rv, f.index)
+		if err != nil {
+			return u.errorf(propPath, err, "failed to access field %s", f.name)
+		}
+
+		val := prop.val
+		if f.asString {
+			val, err = u.unquoteAsString(val, frv)
+			if err != nil {
+				return u.errorf(propPath, err, "invalid value for string option")
+			}
+		}
+
+		if err := u.unmarshal(val, frv, propPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fieldByIndex returns the field allocating embedded structs through pointers if necessary.
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, fmt.Errorf("embedded pointer to unexported struct %s is nil", rv.Type().Elem())
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+
+	return rv, nil
+}
+
+// unquoteAsString parses the string value which wraps a scalar for fields with the string option.
+func (u unmarshaler) unquoteAsString(val Value, rv reflect.Value) (Value, error) {
+	s, ok := val.(String)
+	if !ok {
+		if _, ok := val.(Null); ok {
+			return val, nil
+		}
+
+		return nil, fmt.Errorf("%s should be string", val.Kind())
+	}
+
+	unquoted, err := ParseString(string(s))
+	if err != nil {
+		return nil, err
+	}
+
+	t := rv.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.String {
+		if _, ok := unquoted.(String); !ok {
+			return nil, fmt.Errorf("%s should be quoted string", s)
+		}
+	}
+
+	return unquoted, nil
+}
+
+// toGo converts the given value into the value whose type is one of
+// nil, bool, Num, string, []interface{} and map[string]interface{}.
+func toGo(val Value) interface{} {
+	switch val := val.(type) {
+	case Bool:
+		return bool(val)
+	case Num:
+		return val
+	case String:
+		return string(val)
+	case Array:
+		converted := make([]interface{}, len(val))
+		for i, elem := range val {
+			converted[i] = toGo(elem)
+		}
+
+		return converted
+	case Object:
+		converted := make(map[string]interface{}, val.Len())
+		val.Range(func(key string, val Value) bool {
+			converted[key] = toGo(val)
+			return true
+		})
+
+		return converted
+	default:
+		return nil
+	}
+}
+
+// integerError returns the error which tells why the number cannot be stored into the integer.
+func (u unmarshaler) integerError(n Num, rv reflect.Value, path valuePath) error {
+	switch {
+	case !n.isInt():
+		return u.errorf(path, nil, "%s is not an integer, so cannot be stored into %s", n, rv.Type())
+	case strings.HasPrefix(n.literal, "-") && isUnsigned(rv.Kind()):
+		return u.errorf(path, nil, "%s is negative, so cannot be stored into %s", n, rv.Type())
+	default:
+		return u.errorf(path, nil, "%s overflows %s", n, rv.Type())
+	}
+}
+
+func isUnsigned(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+func (u unmarshaler) mismatchError(val Value, rv reflect.Value, path valuePath) error {
+	return u.errorf(path, nil, "cannot unmarshal %s into %s", val.Kind(), rv.Type())
+}
+
+func (u unmarshaler) errorf(path valuePath, err error, format string, args ...interface{}) error {
+	unmarshalErr := &UnmarshalError{
+		Path: path.String(),
+		Msg:  fmt.Sprintf(format, args...),
+		Err:  err,
+	}
+	if at, ok := u.positions[unmarshalErr.Path]; ok {
+		unmarshalErr.Line, unmarshalErr.Column, unmarshalErr.Offset = at.line+1, at.start+1, at.offset
+	}
+
+	return unmarshalErr
+}
+
+// UnmarshalError describes which JSON value cannot be stored into Go value and why.
+type UnmarshalError struct {
+	// Path is the path to the value such as $.items[0].id.
+	Path string
+	// Line, Column and Offset locate the value in the same way as SyntaxError.
+	// They are zero if the value is not parsed from source.
+	Line, Column int
+	Offset       int
+	Msg          string
+	Err          error
+}
+
+func (e *UnmarshalError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to unmarshal %s", e.Path)
+	if e.Line != 0 {
+		fmt.Fprintf(&b, " at line %d, column %d", e.Line, e.Column)
+	}
+	fmt.Fprintf(&b, ": %s", e.Msg)
+	if e.Err != nil {
+		fmt.Fprintf(&b, ": %s", e.Err)
+	}
+
+	return b.String()
+}
+
+func (e *UnmarshalError) Unwrap() error {
+	return e.Err
+}
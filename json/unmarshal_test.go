@@ -0,0 +1,316 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testUser struct {
+	testBase
+	testMeta
+	Name     string           `json:"name"`
+	Age      int              `json:"age,omitempty"`
+	Score    float64          `json:"score,string"`
+	Admin    bool             `json:"admin,string"`
+	Tags     []string         `json:"tags"`
+	Scores   map[string]int   `json:"scores"`
+	Friend   *testUser        `json:"friend"`
+	Extra    interface{}      `json:"extra"`
+	Raw      Value            `json:"raw"`
+	Point    [2]int           `json:"point"`
+	Level    testLevel        `json:"level"`
+	Keys     map[testKey]bool `json:"keys"`
+	Ignored  string           `json:"-"`
+	Untagged string
+	private  string
+}
+
+type testBase struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"base_name"`
+}
+
+type testMeta struct {
+	Note string `json:"note"`
+}
+
+type testLevel int
+
+func (l *testLevel) UnmarshalJSONValue(val Value) error {
+	if _, ok := val.(Null); ok {
+		*l = 0
+		return nil
+	}
+
+	s, ok := val.(String)
+	if !ok {
+		return fmt.Errorf("level should be string")
+	}
+
+	switch s {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level: %s", s)
+	}
+
+	return nil
+}
+
+type testKey string
+
+func (k *testKey) UnmarshalText(text []byte) error {
+	*k = testKey(strings.ToUpper(string(text)))
+	return nil
+}
+
+func TestUnmarshal(t *testing.T) {
+	src := `{
+	"id": 18446744073709551615,
+	"note": "embedded",
+	"name": "alice",
+	"age": 20,
+	"score": "98.5",
+	"admin": "true",
+	"tags": ["a", "b"],
+	"scores": {"math": 100},
+	"friend": {"name": "bob", "friend": null},
+	"extra": {"a": [1, "two", true, null]},
+	"raw": [1],
+	"point": [3, 4, 5],
+	"level": "high",
+	"keys": {"k": true},
+	"Ignored": "ignored",
+	"untagged": "matched case-insensitively",
+	"unknown": 1
+}`
+	expected := testUser{
+		testBase: testBase{ID: 18446744073709551615},
+		testMeta: testMeta{Note: "embedded"},
+		Name:     "alice",
+		Age:      20,
+		Score:    98.5,
+		Admin:    true,
+		Tags:     []string{"a", "b"},
+		Scores:   map[string]int{"math": 100},
+		Friend:   &testUser{Name: "bob"},
+		Extra: map[string]interface{}{
+			"a": []interface{}{Num{literal: "1"}, "two", true, nil},
+		},
+		Raw:      Array{Num{literal: "1"}},
+		Point:    [2]int{3, 4},
+		Level:    2,
+		Keys:     map[testKey]bool{"K": true},
+		Untagged: "matched case-insensitively",
+	}
+
+	var actual testUser
+	if err := Unmarshal([]byte(src), &actual); err != nil {
+		t.Errorf("should have unmarshaled: %s", err)
+		return
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("should have unmarshaled: %s", reportUnexpected("value", fmt.Sprintf("%+v", actual), fmt.Sprintf("%+v", expected)))
+		return
+	}
+}
+
+func TestUnmarshalIntoExistingValue(t *testing.T) {
+	actual := map[string]interface{}{
+		"a": 1,
+	}
+	if err := Unmarshal([]byte(`{"b": "two"}`), &actual); err != nil {
+		t.Errorf("should have unmarshaled: %s", err)
+		return
+	}
+
+	expected := map[string]interface{}{
+		"a": 1,
+		"b": "two",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("should have unmarshaled: %s", reportUnexpected("value", actual, expected))
+		return
+	}
+}
+
+func TestUnmarshalError(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		opts     UnmarshalOptions
+		target   interface{}
+		expected UnmarshalError
+	}{
+		"type mismatch": {
+			src:    "{\n\"tags\": [\"a\", 2]\n}",
+			target: &testUser{},
+			expected: UnmarshalError{
+				Path: "$.tags[1]",
+				Line: 2, Column: 15, Offset: 16,
+			},
+		},
+		"overflow": {
+			src:    `{"friend": {"age": 1e100}}`,
+			target: &testUser{},
+			expected: UnmarshalError{
+				Path: "$.friend.age",
+				Line: 1, Column: 20, Offset: 19,
+			},
+		},
+		"unknown field": {
+			src:    `{"name": "alice", "first name": "alice"}`,
+			opts:   UnmarshalOptions{DisallowUnknownFields: true},
+			target: &testUser{},
+			expected: UnmarshalError{
+				Path: `$["first name"]`,
+				Line: 1, Column: 33, Offset: 32,
+			},
+		},
+		"unmarshaler error": {
+			src:    `[{"level": "middle"}]`,
+			target: &[]testUser{},
+			expected: UnmarshalError{
+				Path: "$[0].level",
+				Line: 1, Column: 12, Offset: 11,
+			},
+		},
+		"invalid string option": {
+			src:    `{"score": 98.5}`,
+			target: &testUser{},
+			expected: UnmarshalError{
+				Path: "$.score",
+				Line: 1, Column: 11, Offset: 10,
+			},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			err := test.opts.Unmarshal([]byte(test.src), test.target)
+			var actual *UnmarshalError
+			if !errors.As(err, &actual) {
+				t.Errorf("should have returned UnmarshalError: %v", err)
+				return
+			}
+			if actual.Path != test.expected.Path {
+				t.Errorf("should have returned UnmarshalError: %s", reportUnexpected("path", actual.Path, test.expected.Path))
+				return
+			}
+			if actual.Line != test.expected.Line || actual.Column != test.expected.Column || actual.Offset != test.expected.Offset {
+				t.Errorf("should have returned UnmarshalError: %s", reportUnexpected(
+					"position",
+					[]int{actual.Line, actual.Column, actual.Offset},
+					[]int{test.expected.Line, test.expected.Column, test.expected.Offset},
+				))
+				return
+			}
+		})
+	}
+}
+
+func TestUnmarshalNumError(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		target   interface{}
+		expected string
+	}{
+		"fraction into int": {
+			src:      `1.5`,
+			target:   new(int),
+			expected: "1.5 is not an integer, so cannot be stored into int",
+		},
+		"fraction into uint": {
+			src:      `-0.5`,
+			target:   new(uint),
+			expected: "-0.5 is not an integer, so cannot be stored into uint",
+		},
+		"negative into uint": {
+			src:      `-1`,
+			target:   new(uint8),
+			expected: "-1 is negative, so cannot be stored into uint8",
+		},
+		"overflow int": {
+			src:      `128`,
+			target:   new(int8),
+			expected: "128 overflows int8",
+		},
+		"overflow uint": {
+			src:      `1e20`,
+			target:   new(uint64),
+			expected: "1e20 overflows uint64",
+		},
+		"underflow int": {
+			src:      `-1e100`,
+			target:   new(int64),
+			expected: "-1e100 overflows int64",
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			err := Unmarshal([]byte(test.src), test.target)
+			var actual *UnmarshalError
+			if !errors.As(err, &actual) {
+				t.Errorf("should have returned UnmarshalError: %v", err)
+				return
+			}
+			if actual.Msg != test.expected {
+				t.Errorf("should have told why the number cannot be stored: %s", reportUnexpected("message", actual.Msg, test.expected))
+				return
+			}
+		})
+	}
+}
+
+func TestUnmarshalBytes(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected []byte
+	}{
+		"base64": {src: `"aGVsbG8="`, expected: []byte("hello")},
+		"empty":  {src: `""`, expected: []byte{}},
+		"array":  {src: `[104, 105]`, expected: []byte("hi")},
+		"null":   {src: `null`},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			var actual []byte
+			if err := Unmarshal([]byte(test.src), &actual); err != nil {
+				t.Errorf("should have unmarshaled: %s", err)
+				return
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("should have unmarshaled: %s", reportUnexpected("bytes", actual, test.expected))
+				return
+			}
+		})
+	}
+
+	var actual []byte
+	if err := Unmarshal([]byte(`"not base64!"`), &actual); err == nil {
+		t.Errorf("should have failed to unmarshal invalid base64")
+		return
+	}
+}
+
+func TestUnmarshalTrailingData(t *testing.T) {
+	var actual map[string]interface{}
+	if err := Unmarshal([]byte(`{"n":"12","x":3} trailing`), &actual); err == nil {
+		t.Errorf("should have failed to unmarshal the source with trailing data")
+		return
+	}
+}
+
+func TestUnmarshalInvalidTarget(t *testing.T) {
+	var user testUser
+	if err := Unmarshal([]byte(`{}`), user); err == nil {
+		t.Errorf("should have failed to unmarshal into non pointer")
+		return
+	}
+}
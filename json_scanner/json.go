@@ -55,6 +55,24 @@ func (v *scannableVal) Scan(state fmt.ScanState, _ rune) error {
 
 		v.val = val
 
+		return nil
+	case canBeBool(string(peeked)):
+		var val boolVal
+		if _, err := fmt.Fscan(state, &val); err != nil {
+			return fmt.Errorf("failed to parse bool: %w", err)
+		}
+
+		v.val = val
+
+		return nil
+	case canBeNull(string(peeked)):
+		var val nullVal
+		if _, err := fmt.Fscan(state, &val); err != nil {
+			return fmt.Errorf("failed to parse null: %w", err)
+		}
+
+		v.val = val
+
 		return nil
 	default:
 		var val intVal
@@ -92,6 +110,22 @@ func canBeString(src string) bool {
 	return src[0] == '"'
 }
 
+func canBeBool(src string) bool {
+	if len(src) < 1 {
+		return false
+	}
+
+	return src[0] == 't' || src[0] == 'f'
+}
+
+func canBeNull(src string) bool {
+	if len(src) < 1 {
+		return false
+	}
+
+	return src[0] == 'n'
+}
+
 func parseObject(src string) (object, error) {
 	var val object
 	if _, err := fmt.Sscan(src, &val); err != nil {
@@ -331,6 +365,73 @@ func (v *stringVal) Scan(state fmt.ScanState, _ rune) error {
 	return nil
 }
 
+func parseBool(src string) (boolVal, error) {
+	var val boolVal
+	if _, err := fmt.Sscan(src, &val); err != nil {
+		return false, err
+	}
+
+	return val, nil
+}
+
+type boolVal bool
+
+func (boolVal) value() {}
+
+func (v *boolVal) Scan(state fmt.ScanState, _ rune) error {
+	read, err := scanLiteral(state)
+	if err != nil {
+		return err
+	}
+
+	switch read {
+	case "true":
+		*v = true
+	case "false":
+		*v = false
+	default:
+		return fmt.Errorf("invalid format: bool should be true or false: %s", read)
+	}
+
+	return nil
+}
+
+func parseNull(src string) (nullVal, error) {
+	var val nullVal
+	if _, err := fmt.Sscan(src, &val); err != nil {
+		return nullVal{}, err
+	}
+
+	return val, nil
+}
+
+type nullVal struct{}
+
+func (nullVal) value() {}
+
+func (v *nullVal) Scan(state fmt.ScanState, _ rune) error {
+	read, err := scanLiteral(state)
+	if err != nil {
+		return err
+	}
+	if read != "null" {
+		return fmt.Errorf("invalid format: null should be null: %s", read)
+	}
+
+	return nil
+}
+
+func scanLiteral(state fmt.ScanState) (string, error) {
+	read, err := state.Token(true, func(r rune) bool {
+		return 'a' <= r && r <= 'z'
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(read), nil
+}
+
 func peek(s io.RuneScanner) (rune, error) {
 	r, _, err := s.ReadRune()
 	if err != nil {
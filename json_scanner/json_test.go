@@ -26,6 +26,18 @@ func TestParse(t *testing.T) {
 			src:      `"aiueo"`,
 			expected: stringVal("aiueo"),
 		},
+		"true": {
+			src:      "true",
+			expected: boolVal(true),
+		},
+		"false": {
+			src:      "false",
+			expected: boolVal(false),
+		},
+		"null": {
+			src:      "null",
+			expected: nullVal{},
+		},
 	}
 
 	for n, test := range tests {
@@ -54,6 +66,10 @@ func assertValue(actual, expected value) error {
 		return assertInt(actual.(intVal), expected)
 	case stringVal:
 		return assertString(actual.(stringVal), expected)
+	case boolVal:
+		return assertBool(actual.(boolVal), expected)
+	case nullVal:
+		return assertNull(actual.(nullVal), expected)
 	default:
 		return fmt.Errorf("unknown type")
 	}
@@ -66,7 +82,9 @@ func TestParseObject(t *testing.T) {
 		"c": {
 			"d": 2
 		},
-		"e": []
+		"e": [],
+		"f": true,
+		"g": null
 	}`
 	expected := object{
 		props: []prop{
@@ -93,6 +111,14 @@ func TestParseObject(t *testing.T) {
 				key: "e",
 				val: array{},
 			},
+			{
+				key: "f",
+				val: boolVal(true),
+			},
+			{
+				key: "g",
+				val: nullVal{},
+			},
 		},
 	}
 	actual, err := parseObject(src)
@@ -154,13 +180,17 @@ func TestParseArray(t *testing.T) {
 		1, 
 		"a", 
 		2, 
-		3
+		3,
+		false,
+		null
 	]`
 	expected := array{
 		intVal(1),
 		stringVal("a"),
 		intVal(2),
 		intVal(3),
+		boolVal(false),
+		nullVal{},
 	}
 	actual, err := parseArray(src)
 	if err != nil {
@@ -233,6 +263,68 @@ func assertString(actual, expected stringVal) error {
 	return nil
 }
 
+func TestParseBool(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected boolVal
+	}{
+		"true": {
+			src:      "true",
+			expected: boolVal(true),
+		},
+		"false": {
+			src:      "false",
+			expected: boolVal(false),
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			actual, err := parseBool(test.src)
+			if err != nil {
+				t.Errorf("should have parsed bool: %s", err)
+				return
+			}
+
+			if err := assertBool(actual, test.expected); err != nil {
+				t.Errorf("should have parsed bool: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func assertBool(actual, expected boolVal) error {
+	if actual != expected {
+		return reportUnexpected("val", actual, expected)
+	}
+
+	return nil
+}
+
+func TestParseNull(t *testing.T) {
+	src := `null`
+	expected := nullVal{}
+	actual, err := parseNull(src)
+	if err != nil {
+		t.Errorf("should have parsed null: %s", err)
+		return
+	}
+
+	if err := assertNull(actual, expected); err != nil {
+		t.Errorf("should have parsed null: %s", err)
+		return
+	}
+}
+
+func assertNull(actual, expected nullVal) error {
+	if actual != expected {
+		return reportUnexpected("val", actual, expected)
+	}
+
+	return nil
+}
+
 func reportUnexpected(name string, actual, expected interface{}) error {
 	return fmt.Errorf("unexpected %s: got %v, but expected %v", name, actual, expected)
 }
@@ -0,0 +1,282 @@
+package yaml
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tomocy/go-cookbook/internal/orderedmap"
+)
+
+// NewDictinary returns the dictionary which has the given props in the order.
+// The props with the same key are all kept, where the last one is effective.
+func NewDictinary(props ...Prop) Dictinary {
+	dict := Dictinary{
+		m: &orderedmap.Map{},
+	}
+	for _, prop := range props {
+		dict.append(prop)
+	}
+
+	return dict
+}
+
+// DictinaryFromMap returns the dictionary which has the props converted from the given map in the order of their keys.
+// The values should be nil, bool, int, string, []interface{} or map[string]interface{}.
+func DictinaryFromMap(m map[string]interface{}) (Dictinary, error) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	dict := NewDictinary()
+	for _, key := range keys {
+		val, err := fromGo(m[key])
+		if err != nil {
+			return Dictinary{}, fmt.Errorf("failed to convert value of %q: %w", key, err)
+		}
+		dict.Set(key, val)
+	}
+
+	return dict, nil
+}
+
+func fromGo(v interface{}) (value, error) {
+	switch v := v.(type) {
+	case nil:
+		return Null{}, nil
+	case bool:
+		return Bool(v), nil
+	case int:
+		return Num(v), nil
+	case string:
+		return newString(v), nil
+	case []interface{}:
+		arr := make(Array, len(v))
+		for i, elem := range v {
+			val, err := fromGo(elem)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert value at %d: %w", i, err)
+			}
+			arr[i] = val
+		}
+		return arr, nil
+	case map[string]interface{}:
+		return DictinaryFromMap(v)
+	default:
+		return nil, fmt.Errorf("unsupported type: %T", v)
+	}
+}
+
+// Dictinary is a map keeping its props in the order they are added,
+// which takes constant time to get, set and delete the props by their keys.
+// The keys are given in the same way as they are written in the source, so both a and "a" are the key "a".
+//
+// Dictinary refers to its props in the same way as Go maps do, so its copies share the props with it.
+// The zero value is an empty dictionary, to which props can be set.
+type Dictinary struct {
+	m *orderedmap.Map
+}
+
+func (Dictinary) value() {}
+
+// Len returns the number of the props including the ones with the same key.
+func (d Dictinary) Len() int {
+	if d.m == nil {
+		return 0
+	}
+
+	return d.m.Len()
+}
+
+// Get returns the value of the prop with the given key, which is the last one among the ones with the key.
+func (d Dictinary) Get(key string) (value, bool) {
+	if d.m == nil {
+		return nil, false
+	}
+
+	i := d.m.Lookup(string(newString(key)))
+	if i < 0 {
+		return nil, false
+	}
+
+	return propValue(d.m.At(i)), true
+}
+
+// Has reports whether the dictionary has the prop with the given key.
+func (d Dictinary) Has(key string) bool {
+	_, ok := d.Get(key)
+	return ok
+}
+
+// Set replaces the value of the last prop with the given key keeping its position,
+// or adds the prop as the last one if the dictionary does not have it.
+func (d *Dictinary) Set(key string, val value) {
+	if d.m == nil {
+		d.m = &orderedmap.Map{}
+	}
+
+	k := newString(key)
+	if i := d.m.Lookup(string(k)); i >= 0 {
+		d.m.SetAt(i, val)
+		return
+	}
+
+	d.append(Prop{
+		key: k,
+		val: val,
+	})
+}
+
+// Delete deletes all the props with the given key, and reports whether the dictionary had them.
+func (d *Dictinary) Delete(key string) bool {
+	if d.m == nil {
+		return false
+	}
+
+	return d.m.Delete(string(newString(key)))
+}
+
+// Props returns the props in the order, where the props with the same key are all included.
+func (d Dictinary) Props() []Prop {
+	props := make([]Prop, 0, d.Len())
+	d.Range(func(key String, val value) bool {
+		props = append(props, Prop{
+			key: key,
+			val: val,
+		})
+		return true
+	})
+
+	return props
+}
+
+// Range calls fn for each prop in the order until fn returns false.
+func (d Dictinary) Range(fn func(key String, val value) bool) {
+	if d.m == nil {
+		return
+	}
+
+	d.m.Range(func(_ int, key string, val interface{}) bool {
+		return fn(String(key), propValue(val))
+	})
+}
+
+// ToMap converts the dictionary into the map, where the strings lose their quotations,
+// the arrays are converted into []interface{} and the dictionaries are converted into map[string]interface{}.
+func (d Dictinary) ToMap() map[string]interface{} {
+	m := make(map[string]interface{}, d.Len())
+	d.Range(func(key String, val value) bool {
+		m[key.unquoted()] = toGo(val)
+		return true
+	})
+
+	return m
+}
+
+func toGo(val value) interface{} {
+	switch val := val.(type) {
+	case Num:
+		return int(val)
+	case String:
+		return val.unquoted()
+	case Bool:
+		return bool(val)
+	case Array:
+		converted := make([]interface{}, len(val))
+		for i, elem := range val {
+			converted[i] = toGo(elem)
+		}
+		return converted
+	case Dictinary:
+		return val.ToMap()
+	default:
+		return nil
+	}
+}
+
+// Equal reports whether the dictionary has the same props as the other regardless of their order,
+// where only the last props are compared among the ones with the same key.
+func (d Dictinary) Equal(other Dictinary) bool {
+	props := d.effectiveProps()
+	if len(props) != len(other.effectiveProps()) {
+		return false
+	}
+	for _, prop := range props {
+		i := -1
+		if other.m != nil {
+			i = other.m.Lookup(string(prop.key))
+		}
+		if i < 0 || !equalValues(prop.val, propValue(other.m.At(i))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalValues(a, b value) bool {
+	switch a := a.(type) {
+	case Array:
+		b, ok := b.(Array)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalValues(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case Dictinary:
+		b, ok := b.(Dictinary)
+		return ok && a.Equal(b)
+	default:
+		return a == b
+	}
+}
+
+// Clone returns the copy of the dictionary which can be modified independently.
+// The values of the props are shared between them.
+func (d Dictinary) Clone() Dictinary {
+	if d.m == nil {
+		return NewDictinary()
+	}
+
+	return Dictinary{
+		m: d.m.Clone(),
+	}
+}
+
+func (d Dictinary) effectiveProps() []Prop {
+	props := make([]Prop, 0, d.Len())
+	if d.m == nil {
+		return props
+	}
+
+	d.m.Range(func(i int, key string, val interface{}) bool {
+		if d.m.Lookup(key) == i {
+			props = append(props, Prop{
+				key: String(key),
+				val: propValue(val),
+			})
+		}
+		return true
+	})
+
+	return props
+}
+
+func (d *Dictinary) append(prop Prop) {
+	if d.m == nil {
+		d.m = &orderedmap.Map{}
+	}
+
+	d.m.Append(string(prop.key), prop.val)
+}
+
+// propValue returns the value kept in orderedmap.Map, which is nil if the prop is given nil.
+func propValue(val interface{}) value {
+	v, _ := val.(value)
+	return v
+}
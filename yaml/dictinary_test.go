@@ -0,0 +1,206 @@
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/tomocy/go-cookbook/internal/orderedmap"
+)
+
+func TestDictinary(t *testing.T) {
+	tests := map[string]struct {
+		dict     Dictinary
+		update   func(dict *Dictinary)
+		expected Dictinary
+	}{
+		"set to zero value": {
+			dict: Dictinary{},
+			update: func(dict *Dictinary) {
+				dict.Set("a", Num(1))
+			},
+			expected: NewDictinary(Prop{key: String(`"a"`), val: Num(1)}),
+		},
+		"set new key": {
+			dict: NewDictinary(Prop{key: String(`"b"`), val: Num(1)}),
+			update: func(dict *Dictinary) {
+				dict.Set("a", Num(2))
+			},
+			expected: NewDictinary(Prop{key: String(`"b"`), val: Num(1)}, Prop{key: String(`"a"`), val: Num(2)}),
+		},
+		"set existing key in place": {
+			dict: NewDictinary(Prop{key: String(`"a"`), val: Num(1)}, Prop{key: String(`"b"`), val: Num(2)}),
+			update: func(dict *Dictinary) {
+				dict.Set(`"a"`, Num(3))
+			},
+			expected: NewDictinary(Prop{key: String(`"a"`), val: Num(3)}, Prop{key: String(`"b"`), val: Num(2)}),
+		},
+		"delete duplicate key": {
+			dict: NewDictinary(Prop{key: String(`"a"`), val: Num(1)}, Prop{key: String(`"b"`), val: Num(2)}, Prop{key: String(`"a"`), val: Num(3)}),
+			update: func(dict *Dictinary) {
+				dict.Delete("a")
+			},
+			expected: NewDictinary(Prop{key: String(`"b"`), val: Num(2)}),
+		},
+		"set deleted key": {
+			dict: NewDictinary(Prop{key: String(`"a"`), val: Num(1)}, Prop{key: String(`"b"`), val: Num(2)}),
+			update: func(dict *Dictinary) {
+				dict.Delete("a")
+				dict.Set("a", Num(3))
+			},
+			expected: NewDictinary(Prop{key: String(`"b"`), val: Num(2)}, Prop{key: String(`"a"`), val: Num(3)}),
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			test.update(&test.dict)
+			if err := assertDictinary(test.dict, test.expected); err != nil {
+				t.Errorf("unexpected dictionary: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestDictinaryGet(t *testing.T) {
+	dict := NewDictinary(
+		Prop{key: String(`"a"`), val: Num(1)},
+		Prop{key: String(`"b"`), val: Num(2)},
+		Prop{key: String(`"a"`), val: Num(3)},
+	)
+
+	tests := map[string]struct {
+		key      string
+		expected value
+	}{
+		"key":           {key: "b", expected: Num(2)},
+		"quoted key":    {key: `"b"`, expected: Num(2)},
+		"duplicate key": {key: "a", expected: Num(3)},
+		"unknown key":   {key: "c"},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			actual, ok := dict.Get(test.key)
+			if ok != (test.expected != nil) || dict.Has(test.key) != ok {
+				t.Errorf("unexpected existence: %s", reprotUnexpected("ok", ok, test.expected != nil))
+				return
+			}
+			if !ok {
+				return
+			}
+			if err := assertValue(actual, test.expected); err != nil {
+				t.Errorf("unexpected value: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func TestDictinaryWithManyProps(t *testing.T) {
+	dict := NewDictinary()
+	for i := 0; i < orderedmap.IndexThreshold*4; i++ {
+		dict.Set(fmt.Sprint(i), Num(i))
+	}
+	for i := 0; i < orderedmap.IndexThreshold*4; i += 2 {
+		if !dict.Delete(fmt.Sprint(i)) {
+			t.Errorf("should have deleted %d", i)
+			return
+		}
+	}
+	dict.Set("0", Num(-1))
+
+	expected := NewDictinary()
+	for i := 1; i < orderedmap.IndexThreshold*4; i += 2 {
+		expected.Set(fmt.Sprint(i), Num(i))
+	}
+	expected.Set("0", Num(-1))
+
+	if err := assertDictinary(dict, expected); err != nil {
+		t.Errorf("unexpected dictionary: %s", err)
+		return
+	}
+	for i := 1; i < orderedmap.IndexThreshold*4; i += 2 {
+		if actual, _ := dict.Get(fmt.Sprint(i)); actual != Num(i) {
+			t.Errorf("unexpected value: %s", reprotUnexpected(fmt.Sprint(i), actual, Num(i)))
+			return
+		}
+	}
+}
+
+func TestDictinaryClone(t *testing.T) {
+	dict := NewDictinary(Prop{key: String(`"a"`), val: Num(1)})
+	cloned := dict.Clone()
+	cloned.Set("a", Num(2))
+
+	if actual, _ := dict.Get("a"); actual != Num(1) {
+		t.Errorf("should not have modified the original: %s", reprotUnexpected("a", actual, Num(1)))
+		return
+	}
+}
+
+func TestDictinaryMap(t *testing.T) {
+	m := map[string]interface{}{
+		"b": 1,
+		"a": []interface{}{"two", true, nil},
+		"c": map[string]interface{}{
+			"d": "e",
+		},
+	}
+
+	dict, err := DictinaryFromMap(m)
+	if err != nil {
+		t.Errorf("should have converted: %s", err)
+		return
+	}
+
+	expected := NewDictinary(
+		Prop{key: String(`"a"`), val: Array{String(`"two"`), Bool(true), Null{}}},
+		Prop{key: String(`"b"`), val: Num(1)},
+		Prop{key: String(`"c"`), val: NewDictinary(Prop{key: String(`"d"`), val: String(`"e"`)})},
+	)
+	if err := assertDictinary(dict, expected); err != nil {
+		t.Errorf("unexpected dictionary: %s", err)
+		return
+	}
+	if actual := dict.ToMap(); !reflect.DeepEqual(actual, m) {
+		t.Errorf("should have given back the map: %s", reprotUnexpected("map", actual, m))
+		return
+	}
+}
+
+func TestDictinaryEqual(t *testing.T) {
+	tests := map[string]struct {
+		dict, other Dictinary
+		expected    bool
+	}{
+		"same props in different order": {
+			dict:     NewDictinary(Prop{key: String(`"a"`), val: Num(1)}, Prop{key: String(`"b"`), val: Array{Num(2)}}),
+			other:    NewDictinary(Prop{key: String(`"b"`), val: Array{Num(2)}}, Prop{key: String(`"a"`), val: Num(1)}),
+			expected: true,
+		},
+		"shadowed props": {
+			dict:     NewDictinary(Prop{key: String(`"a"`), val: Num(1)}, Prop{key: String(`"a"`), val: Num(2)}),
+			other:    NewDictinary(Prop{key: String(`"a"`), val: Num(2)}),
+			expected: true,
+		},
+		"different values": {
+			dict:  NewDictinary(Prop{key: String(`"a"`), val: Num(1)}),
+			other: NewDictinary(Prop{key: String(`"a"`), val: String(`"1"`)}),
+		},
+		"different keys": {
+			dict:  NewDictinary(Prop{key: String(`"a"`), val: Num(1)}),
+			other: NewDictinary(Prop{key: String(`"b"`), val: Num(1)}),
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			if actual := test.dict.Equal(test.other); actual != test.expected {
+				t.Errorf("unexpected equality: %s", reprotUnexpected("equality", actual, test.expected))
+				return
+			}
+		})
+	}
+}
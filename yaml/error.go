@@ -0,0 +1,29 @@
+package yaml
+
+import (
+	"fmt"
+
+	"github.com/tomocy/go-cookbook/internal/syntaxerr"
+)
+
+// SyntaxError describes where and why the source is not valid YAML.
+type SyntaxError = syntaxerr.SyntaxError
+
+func newSyntaxError(at pos, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{
+		Msg:    fmt.Sprintf(format, args...),
+		Line:   at.line + 1,
+		Column: at.column + 1,
+		Offset: at.offset,
+	}
+}
+
+func newUnexpectedTokenError(tok token, msg string, expected ...tokenKind) *SyntaxError {
+	err := newSyntaxError(tok.pos, "%s", msg)
+	for _, kind := range expected {
+		err.Expected = append(err.Expected, string(kind))
+	}
+	err.Found = string(tok.kind)
+
+	return err
+}
@@ -0,0 +1,81 @@
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSyntaxError(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected SyntaxError
+	}{
+		"number out of range": {
+			src: "a: 1\nb: 99999999999",
+			expected: SyntaxError{
+				Line: 2, Column: 4, Offset: 8,
+				Excerpt: "b: 99999999999\n   ^",
+			},
+		},
+		"unknown token": {
+			src: "- é",
+			expected: SyntaxError{
+				Line: 1, Column: 3, Offset: 2,
+				Expected: []string{"-", "number", "string", "bool"},
+				Found:    "unknown",
+				Excerpt:  "- é\n  ^",
+			},
+		},
+		"unknown token after tab": {
+			src: "a:\n\té",
+			expected: SyntaxError{
+				Line: 2, Column: 2, Offset: 4,
+				Expected: []string{"-", "number", "string", "bool"},
+				Found:    "unknown",
+				Excerpt:  "\té\n\t^",
+			},
+		},
+	}
+
+	for n, test := range tests {
+		t.Run(n, func(t *testing.T) {
+			lex := newLexer([]rune(test.src))
+			p := newParser(lex)
+
+			_, err := p.parse()
+			var actual *SyntaxError
+			if !errors.As(err, &actual) {
+				t.Errorf("should have returned SyntaxError: %v", err)
+				return
+			}
+			if err := assertSyntaxError(*actual, test.expected); err != nil {
+				t.Errorf("should have returned SyntaxError: %s", err)
+				return
+			}
+		})
+	}
+}
+
+func assertSyntaxError(actual, expected SyntaxError) error {
+	if actual.Line != expected.Line {
+		return reprotUnexpected("line", actual.Line, expected.Line)
+	}
+	if actual.Column != expected.Column {
+		return reprotUnexpected("column", actual.Column, expected.Column)
+	}
+	if actual.Offset != expected.Offset {
+		return reprotUnexpected("offset", actual.Offset, expected.Offset)
+	}
+	if fmt.Sprint(actual.Expected) != fmt.Sprint(expected.Expected) {
+		return reprotUnexpected("expected", actual.Expected, expected.Expected)
+	}
+	if actual.Found != expected.Found {
+		return reprotUnexpected("found", actual.Found, expected.Found)
+	}
+	if actual.Excerpt != expected.Excerpt {
+		return reprotUnexpected("excerpt", fmt.Sprintf("%q", actual.Excerpt), fmt.Sprintf("%q", expected.Excerpt))
+	}
+
+	return nil
+}
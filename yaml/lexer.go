@@ -2,6 +2,9 @@ package yaml
 
 import (
 	"fmt"
+	"unicode/utf8"
+
+	"github.com/tomocy/go-cookbook/internal/syntaxerr"
 )
 
 func newLexer(src []rune) lexer {
@@ -74,8 +77,10 @@ func (l *lexer) composeStringWithQuotes() token {
 	t := token{
 		kind: tokenString,
 		pos: pos{
-			line:  l.pos.line,
-			start: l.pos.start,
+			line:   l.pos.line,
+			start:  l.pos.start,
+			column: l.pos.column,
+			offset: l.pos.offset,
 		},
 	}
 	t.literal = l.readString()
@@ -101,8 +106,10 @@ func (l *lexer) composeNum() token {
 	t := token{
 		kind: tokenNum,
 		pos: pos{
-			line:  l.pos.line,
-			start: l.pos.start,
+			line:   l.pos.line,
+			start:  l.pos.start,
+			column: l.pos.column,
+			offset: l.pos.offset,
 		},
 	}
 	t.literal = l.readNum()
@@ -123,8 +130,10 @@ func (l *lexer) readNum() string {
 func (l *lexer) composeLetters() token {
 	t := token{
 		pos: pos{
-			line:  l.pos.line,
-			start: l.pos.start,
+			line:   l.pos.line,
+			start:  l.pos.start,
+			column: l.pos.column,
+			offset: l.pos.offset,
 		},
 	}
 
@@ -183,6 +192,9 @@ func (l *lexer) movePos() {
 	if l.willReadFirstChar() {
 		c = 0
 	}
+	if c != charEOF {
+		l.pos.offset += utf8.RuneLen(c)
+	}
 	l.pos.move(c)
 }
 
@@ -206,6 +218,25 @@ func (l lexer) nextChar() rune {
 	return l.src[l.nextIndex]
 }
 
+// excerpt returns the line where the given pos is with a caret under the pos.
+func (l lexer) excerpt(at pos) string {
+	var line []rune
+	for i, n := 0, 0; i < len(l.src); i++ {
+		if l.src[i] == '\n' {
+			n++
+			continue
+		}
+		if n == at.line {
+			line = append(line, l.src[i])
+		}
+		if n > at.line {
+			break
+		}
+	}
+
+	return syntaxerr.Excerpt(line, at.column)
+}
+
 func isNum(c rune) bool {
 	return '0' <= c && c <= '9'
 }
@@ -238,6 +269,10 @@ const (
 	tokenBool   tokenKind = "bool"
 )
 
+var valueTokenKinds = []tokenKind{
+	tokenHyphen, tokenNum, tokenString, tokenBool,
+}
+
 var tokenKinds = map[string]tokenKind{
 	"\x00":  tokenEOF,
 	"-":     tokenHyphen,
@@ -247,8 +282,14 @@ var tokenKinds = map[string]tokenKind{
 }
 
 type pos struct {
-	line       int
+	line int
+	// start and end are the columns where the tabs are expanded into spaces,
+	// with which the parser compares the indents.
 	start, end int
+	// column is the number of the characters before start in the line, where a tab is a character.
+	column int
+	// offset is the byte offset of start from the beginning of the source.
+	offset int
 }
 
 const (
@@ -258,16 +299,18 @@ const (
 func (p *pos) move(c rune) {
 	if c == '\n' {
 		p.line++
-		p.start, p.end = 0, 1
+		p.start, p.end, p.column = 0, 1, 0
 		return
 	}
-	if c == '\t' {
-		for i := 0; i < spacesInTab; i++ {
-			p.move(' ')
-		}
-		return
+	// The pos is moved onto the first character without any character before it.
+	if p.end != 0 {
+		p.column++
 	}
 
-	p.start = p.end
-	p.end++
+	width := 1
+	if c == '\t' {
+		width = spacesInTab
+	}
+	p.start = p.end + width - 1
+	p.end = p.start + 1
 }
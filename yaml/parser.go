@@ -64,10 +64,10 @@ func (p *parser) parse() (value, error) {
 
 			return val, nil
 		default:
-			return nil, fmt.Errorf("unknown type of token: %s", p.currTok.kind)
+			return nil, p.unexpectedTokenError("unknown type of token", valueTokenKinds...)
 		}
 	default:
-		return nil, fmt.Errorf("unknown type of token: %s", p.currTok.kind)
+		return nil, p.unexpectedTokenError("unknown type of token", valueTokenKinds...)
 	}
 }
 
@@ -96,14 +96,14 @@ func (p *parser) parseArray() (Array, error) {
 func (p *parser) parseDictinary() (Dictinary, error) {
 	basePos := p.currTok.pos
 
-	var obj Dictinary
+	obj := NewDictinary()
 	for {
 		prop, err := p.parseProp()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse prop: %w", err)
+			return Dictinary{}, fmt.Errorf("failed to parse prop: %w", err)
 		}
 
-		obj = append(obj, prop)
+		obj.append(prop)
 
 		if !p.doHaveTokenInBase(tokenString, basePos.start) || !p.willHaveToken(tokenColon) {
 			break
@@ -120,7 +120,7 @@ func (p *parser) parseProp() (Prop, error) {
 	}
 
 	if !p.doHaveToken(tokenColon) {
-		return Prop{}, fmt.Errorf("invalid prop format: prop should be composed of key and value separated by ':'")
+		return Prop{}, p.unexpectedTokenError("invalid prop format: prop should be composed of key and value separated by ':'", tokenColon)
 	}
 	p.readToken()
 
@@ -138,7 +138,7 @@ func (p *parser) parseProp() (Prop, error) {
 func (p *parser) parseNum() (Num, error) {
 	parsed, err := strconv.ParseInt(p.currTok.literal, 10, 32)
 	if err != nil {
-		return 0, err
+		return 0, p.withExcerpt(newSyntaxError(p.currTok.pos, "invalid number format: %s", err))
 	}
 
 	p.readToken()
@@ -147,14 +147,19 @@ func (p *parser) parseNum() (Num, error) {
 }
 
 func (p *parser) parseString() (String, error) {
-	quoted := p.currTok.literal
-	if !isStringQuoted(quoted) {
-		quoted = fmt.Sprintf("\"%s\"", quoted)
-	}
+	quoted := newString(p.currTok.literal)
 
 	p.readToken()
 
-	return String(quoted), nil
+	return quoted, nil
+}
+
+func newString(s string) String {
+	if !isStringQuoted(s) {
+		s = fmt.Sprintf("\"%s\"", s)
+	}
+
+	return String(s)
 }
 
 func isStringQuoted(s string) bool {
@@ -176,7 +181,20 @@ func (p *parser) parseBool() (Bool, error) {
 		return false, nil
 	}
 
-	return false, fmt.Errorf("invalid literal of bool: %s", l)
+	return false, p.withExcerpt(newSyntaxError(p.currTok.pos, "invalid literal of bool: %s", l))
+}
+
+func (p *parser) unexpectedTokenError(msg string, expected ...tokenKind) *SyntaxError {
+	return p.withExcerpt(newUnexpectedTokenError(p.currTok, msg, expected...))
+}
+
+func (p *parser) withExcerpt(err *SyntaxError) *SyntaxError {
+	err.Excerpt = p.lex.excerpt(pos{
+		line:   err.Line - 1,
+		column: err.Column - 1,
+	})
+
+	return err
 }
 
 func (p *parser) readToken() {
@@ -212,6 +230,10 @@ type String string
 
 func (String) value() {}
 
+func (s String) unquoted() string {
+	return string(s[1 : len(s)-1])
+}
+
 type Bool bool
 
 func (Bool) value() {}
@@ -220,10 +242,6 @@ type Array []value
 
 func (Array) value() {}
 
-type Dictinary []Prop
-
-func (Dictinary) value() {}
-
 type Prop struct {
 	key String
 	val value
@@ -53,22 +53,22 @@ func TestParse(t *testing.T) {
 					String(`"one"`),
 					Num(2),
 				},
-				Dictinary{
-					{
+				NewDictinary(
+					Prop{
 						key: String(`"five"`),
 						val: Bool(true),
 					},
-				},
-				Dictinary{
-					{
+				),
+				NewDictinary(
+					Prop{
 						key: String(`"6"`),
 						val: Bool(false),
 					},
-					{
+					Prop{
 						key: String(`"seven"`),
 						val: Num(7),
 					},
-				},
+				),
 			},
 		},
 		"dictionary": {
@@ -81,38 +81,38 @@ e:
 f:
   g:
     h: i`,
-			expected: Dictinary{
-				{
+			expected: NewDictinary(
+				Prop{
 					key: String(`"a"`),
 					val: Num(1),
 				},
-				{
+				Prop{
 					key: String(`"b"`),
 					val: String(`"two"`),
 				},
-				{
+				Prop{
 					key: String(`"c"`),
 					val: Num(3),
 				},
-				{
+				Prop{
 					key: String(`"e"`),
 					val: String(`"four"`),
 				},
-				{
+				Prop{
 					key: String(`"f"`),
-					val: Dictinary{
-						{
+					val: NewDictinary(
+						Prop{
 							key: String(`"g"`),
-							val: Dictinary{
-								{
+							val: NewDictinary(
+								Prop{
 									key: String(`"h"`),
 									val: String(`"i"`),
 								},
-							},
+							),
 						},
-					},
+					),
 				},
-			},
+			),
 		},
 		"kubernetes": {
 			src: `apiVersion: apps/v1
@@ -139,106 +139,106 @@ spec:
         - /bin/sleep
         - infinity
 `,
-			expected: Dictinary{
-				{
+			expected: NewDictinary(
+				Prop{
 					key: String(`"apiVersion"`),
 					val: String(`"apps/v1"`),
 				},
-				{
+				Prop{
 					key: String(`"kind"`),
 					val: String(`"Deployment"`),
 				},
-				{
+				Prop{
 					key: String(`"metadata"`),
-					val: Dictinary{
-						{
+					val: NewDictinary(
+						Prop{
 							key: String(`"name"`),
 							val: String(`"app"`),
 						},
-						{
+						Prop{
 							key: String(`"namespace"`),
 							val: String(`"cookbook"`),
 						},
-					},
+					),
 				},
-				{
+				Prop{
 					key: String(`"spec"`),
-					val: Dictinary{
-						{
+					val: NewDictinary(
+						Prop{
 							key: String(`"replicas"`),
 							val: Num(1),
 						},
-						{
+						Prop{
 							key: String(`"selector"`),
-							val: Dictinary{
-								{
+							val: NewDictinary(
+								Prop{
 									key: String(`"matchLabels"`),
-									val: Dictinary{
-										{
+									val: NewDictinary(
+										Prop{
 											key: String(`"app"`),
 											val: String(`"curl"`),
 										},
-										{
+										Prop{
 											key: String(`"version"`),
 											val: String(`"v1"`),
 										},
-									},
+									),
 								},
-							},
+							),
 						},
-						{
+						Prop{
 							key: String(`"template"`),
-							val: Dictinary{
-								{
+							val: NewDictinary(
+								Prop{
 									key: String(`"metadata"`),
-									val: Dictinary{
-										{
+									val: NewDictinary(
+										Prop{
 											key: String(`"labels"`),
-											val: Dictinary{
-												{
+											val: NewDictinary(
+												Prop{
 													key: String(`"app"`),
 													val: String(`"curl"`),
 												},
-												{
+												Prop{
 													key: String(`"version"`),
 													val: String(`"v1"`),
 												},
-											},
+											),
 										},
-									},
+									),
 								},
-								{
+								Prop{
 									key: String(`"spec"`),
-									val: Dictinary{
-										{
+									val: NewDictinary(
+										Prop{
 											key: String(`"containers"`),
 											val: Array{
-												Dictinary{
-													{
+												NewDictinary(
+													Prop{
 														key: String(`"name"`),
 														val: String(`"curl"`),
 													},
-													{
+													Prop{
 														key: String(`"image"`),
 														val: String(`"curlimages/curl"`),
 													},
-													{
+													Prop{
 														key: String(`"command"`),
 														val: Array{
 															String(`"/bin/sleep"`),
 															String(`"infinity"`),
 														},
 													},
-												},
+												),
 											},
 										},
-									},
+									),
 								},
-							},
+							),
 						},
-					},
+					),
 				},
-			},
+			),
 		},
 	}
 
@@ -297,14 +297,15 @@ func assertArray(actual, expected Array) error {
 }
 
 func assertDictinary(actual, expected Dictinary) error {
-	if len(actual) != len(expected) {
-		return reprotUnexpected("len of value", len(actual), len(expected))
+	if actual.Len() != expected.Len() {
+		return reprotUnexpected("len of value", actual.Len(), expected.Len())
 	}
-	for i, expected := range expected {
-		if err := assertValue(actual[i].key, expected.key); err != nil {
+	actualProps := actual.Props()
+	for i, expected := range expected.Props() {
+		if err := assertValue(actualProps[i].key, expected.key); err != nil {
 			return fmt.Errorf("unexpected key: %w", err)
 		}
-		if err := assertValue(actual[i].val, expected.val); err != nil {
+		if err := assertValue(actualProps[i].val, expected.val); err != nil {
 			return fmt.Errorf("unexpected value: %w", err)
 		}
 	}